@@ -0,0 +1,25 @@
+#!/bin/bash
+# Generates a throw-away CA and a client certificate for MONGODB-X509 acceptance tests.
+# Run it from docker/docker-mongo-ssl before building the image.
+
+set -e
+
+SSL_DIR="$(dirname "$0")/../ssl"
+SUBJECT="/O=terraform-provider-mongodb/OU=terraform/CN=tf-acc-x509"
+
+openssl req -x509 -newkey rsa:2048 -nodes -days 365 \
+  -subj "/O=terraform-provider-mongodb/CN=tf-acc-ca" \
+  -keyout "$SSL_DIR/test-ca.key" -out "$SSL_DIR/test-ca.pem"
+
+openssl req -newkey rsa:2048 -nodes -subj "$SUBJECT" \
+  -keyout "$SSL_DIR/client.key" -out "$SSL_DIR/client.csr"
+
+openssl x509 -req -days 365 -in "$SSL_DIR/client.csr" \
+  -CA "$SSL_DIR/test-ca.pem" -CAkey "$SSL_DIR/test-ca.key" -CAcreateserial \
+  -out "$SSL_DIR/client.crt"
+
+cat "$SSL_DIR/client.crt" "$SSL_DIR/client.key" > "$SSL_DIR/client.pem"
+rm -f "$SSL_DIR/client.csr" "$SSL_DIR/test-ca.srl"
+
+echo "Client certificate written to $SSL_DIR/client.pem"
+echo "export MONGODB_CLIENT_CERT=$(cd "$SSL_DIR" && pwd)/client.pem"
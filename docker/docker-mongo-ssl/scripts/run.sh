@@ -2,6 +2,12 @@
 
 sleep 5
 
+# Trust the test CA from gen_client_cert.sh for X.509 client authentication
+cp ssl/ca.pem ssl/ca-bundle.pem
+if [ -f ssl/test-ca.pem ]; then
+  cat ssl/test-ca.pem >> ssl/ca-bundle.pem
+fi
+
 chown -R mongodb:mongodb /home/mongodb
 
 nohup gosu mongodb mongod --dbpath=/data/db &
@@ -10,6 +10,11 @@ nohup gosu mongodb mongo admin --eval "db.createUser({user: 'root', pwd: 'root',
 # create app user/database
 nohup gosu mongodb mongo admin --eval "db.createUser({ user: 'admin', pwd: 'admin', roles: ['userAdminAnyDatabase', 'dbAdminAnyDatabase', 'readWriteAnyDatabase']});"
 
+# create the X.509 user matching the certificate from gen_client_cert.sh
+if [ -f /home/mongodb/ssl/client.pem ]; then
+  nohup gosu mongodb mongo admin --eval "db.getSiblingDB('\$external').createUser({ user: 'CN=tf-acc-x509,OU=terraform,O=terraform-provider-mongodb', roles: [{ role: 'root', db: 'admin' }]});"
+fi
+
 echo "************************************************************"
 echo "Shutting down"
 echo "************************************************************"
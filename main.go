@@ -1,6 +1,9 @@
 package main
 
 import (
+	"context"
+	"log"
+
 	"github.com/FelGel/terraform-provider-mongodb/mongodb"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
 )
@@ -9,4 +12,9 @@ func main() {
 	plugin.Serve(&plugin.ServeOpts{
 		ProviderFunc: mongodb.Provider,
 	})
+
+	// Serve returns once Terraform shuts the plugin down; release pooled connections.
+	if err := mongodb.DisconnectClients(context.Background()); err != nil {
+		log.Printf("[WARN] error disconnecting mongodb clients: %s", err)
+	}
 }
@@ -1,18 +1,29 @@
 package mongodb
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
 	"errors"
 	"fmt"
 	"net/url"
+	"os"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/youmark/pkcs8"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/auth"
 	"golang.org/x/net/proxy"
 )
 
@@ -30,15 +41,41 @@ type ClientConfig struct {
 	Certificate        string
 	Direct             bool
 	Proxy              string
+
+	ClientCertificate            string
+	ClientCertificateKey         string
+	ClientCertificateKeyPassword string
+
+	AuthMechanism           string
+	AuthMechanismProperties map[string]string
 }
+
+// authMechanisms lists the supported auth_mechanism values and whether the
+// mechanism authenticates against the $external database.
+var authMechanisms = map[string]bool{
+	"SCRAM-SHA-1":   false,
+	"SCRAM-SHA-256": false,
+	"PLAIN":         true,
+	"MONGODB-X509":  true,
+	"MONGODB-AWS":   true,
+}
+
 type DbUser struct {
-	Name     string `json:"name"`
-	Password string `json:"password"`
+	Name                       string                      `json:"name" bson:"name"`
+	Password                   string                      `json:"password" bson:"password"`
+	Mechanisms                 []string                    `json:"mechanisms" bson:"mechanisms"`
+	CustomData                 bson.D                      `json:"customData" bson:"customData"`
+	AuthenticationRestrictions []AuthenticationRestriction `json:"authenticationRestrictions" bson:"authenticationRestrictions"`
+}
+
+type AuthenticationRestriction struct {
+	ClientSource  []string `json:"clientSource,omitempty" bson:"clientSource,omitempty"`
+	ServerAddress []string `json:"serverAddress,omitempty" bson:"serverAddress,omitempty"`
 }
 
 type Role struct {
-	Role string `json:"role"`
-	Db   string `json:"db"`
+	Role string `json:"role" bson:"role"`
+	Db   string `json:"db" bson:"db"`
 }
 
 func (role Role) String() string {
@@ -46,42 +83,46 @@ func (role Role) String() string {
 }
 
 type PrivilegeDto struct {
-	Db         string   `json:"db"`
-	Collection string   `json:"collection"`
-	Actions    []string `json:"actions"`
+	Db            string   `json:"db"`
+	Collection    string   `json:"collection"`
+	SystemBuckets bool     `json:"system_buckets" mapstructure:"system_buckets"`
+	Cluster       bool     `json:"cluster"`
+	AnyResource   bool     `json:"any_resource" mapstructure:"any_resource"`
+	Actions       []string `json:"actions"`
 }
 
 type Privilege struct {
-	Resource Resource `json:"resource"`
-	Actions  []string `json:"actions"`
+	Resource Resource `json:"resource" bson:"resource"`
+	Actions  []string `json:"actions" bson:"actions"`
 }
 type SingleResultGetUser struct {
 	Users []struct {
-		Id    string `json:"_id"`
-		User  string `json:"user"`
-		Db    string `json:"db"`
+		Id    string `json:"_id" bson:"_id"`
+		User  string `json:"user" bson:"user"`
+		Db    string `json:"db" bson:"db"`
 		Roles []struct {
-			Role string `json:"role"`
-			Db   string `json:"db"`
-		} `json:"roles"`
-	} `json:"users"`
+			Role string `json:"role" bson:"role"`
+			Db   string `json:"db" bson:"db"`
+		} `json:"roles" bson:"roles"`
+		Mechanisms                 []string      `json:"mechanisms" bson:"mechanisms"`
+		CustomData                 bson.Raw      `json:"customData" bson:"customData"`
+		AuthenticationRestrictions bson.RawValue `json:"authenticationRestrictions" bson:"authenticationRestrictions"`
+	} `json:"users" bson:"users"`
 }
 type SingleResultGetRole struct {
 	Roles []struct {
-		Role           string `json:"role"`
-		Db             string `json:"db"`
+		Role           string `json:"role" bson:"role"`
+		Db             string `json:"db" bson:"db"`
 		InheritedRoles []struct {
-			Role string `json:"role"`
-			Db   string `json:"db"`
-		} `json:"inheritedRoles"`
+			Role string `json:"role" bson:"role"`
+			Db   string `json:"db" bson:"db"`
+		} `json:"inheritedRoles" bson:"inheritedRoles"`
 		Privileges []struct {
-			Resource struct {
-				Db         string `json:"db"`
-				Collection string `json:"collection"`
-			} `json:"resource"`
-			Actions []string `json:"actions"`
-		} `json:"privileges"`
-	} `json:"roles"`
+			Resource Resource `json:"resource" bson:"resource"`
+			Actions  []string `json:"actions" bson:"actions"`
+		} `json:"privileges" bson:"privileges"`
+		AuthenticationRestrictions bson.RawValue `json:"authenticationRestrictions" bson:"authenticationRestrictions"`
+	} `json:"roles" bson:"roles"`
 }
 
 func addArgs(arguments string, newArg string) string {
@@ -100,7 +141,8 @@ func (c *ClientConfig) MongoClient() (*mongo.Client, error) {
 
 	arguments = addArgs(arguments, "retrywrites="+strconv.FormatBool(c.RetryWrites))
 
-	if c.Tls {
+	// A client certificate is only ever presented over TLS
+	if c.Tls || c.ClientCertificate != "" {
 		arguments = addArgs(arguments, "tls=true")
 	}
 
@@ -131,17 +173,22 @@ func (c *ClientConfig) MongoClient() (*mongo.Client, error) {
 	}
 
 	opts := options.Client().ApplyURI(uri).SetDialer(dialer)
-	if len(c.Username) > 0 && len(c.Password) > 0 {
-		opts.SetAuth(options.Credential{
-			AuthSource: c.DB, Username: c.Username, Password: c.Password,
-		})
+	if credential, ok := c.credential(); ok {
+		opts.SetAuth(credential)
 	}
 
-	if c.Certificate != "" || verify {
+	if c.Certificate != "" || verify || c.ClientCertificate != "" {
 		tlsConfig, err := getTLSConfig([]byte(c.Certificate), verify)
 		if err != nil {
 			return nil, err
 		}
+		if c.ClientCertificate != "" {
+			cert, err := loadClientCertificate(c.ClientCertificate, c.ClientCertificateKey, c.ClientCertificateKeyPassword)
+			if err != nil {
+				return nil, err
+			}
+			tlsConfig.Certificates = []tls.Certificate{cert}
+		}
 		opts.SetTLSConfig(tlsConfig)
 	}
 
@@ -150,6 +197,76 @@ func (c *ClientConfig) MongoClient() (*mongo.Client, error) {
 	return client, err
 }
 
+// credential returns the credential to authenticate with, if any. Without an
+// explicit auth_mechanism the driver negotiates SCRAM for username/password,
+// and a lone client certificate selects MONGODB-X509.
+func (c *ClientConfig) credential() (options.Credential, bool) {
+	switch {
+	case c.AuthMechanism != "":
+		credential := options.Credential{
+			AuthMechanism:           c.AuthMechanism,
+			AuthMechanismProperties: c.AuthMechanismProperties,
+			AuthSource:              c.DB,
+			Username:                c.Username,
+			Password:                c.Password,
+		}
+		if authMechanisms[c.AuthMechanism] {
+			credential.AuthSource = "$external"
+		}
+		return credential, true
+	case len(c.Username) > 0 && len(c.Password) > 0:
+		return options.Credential{
+			AuthSource: c.DB, Username: c.Username, Password: c.Password,
+		}, true
+	case c.ClientCertificate != "":
+		// X.509 users live in $external, the subject is taken from the certificate
+		// unless a username is given explicitly.
+		return options.Credential{
+			AuthMechanism: "MONGODB-X509", AuthSource: "$external", Username: c.Username,
+		}, true
+	}
+	return options.Credential{}, false
+}
+
+// validateAuth checks that the configured credentials are legal for the
+// selected auth_mechanism.
+func (c *ClientConfig) validateAuth() error {
+	if c.AuthMechanism != "MONGODB-AWS" && len(c.AuthMechanismProperties) > 0 {
+		return fmt.Errorf("auth_mechanism_properties are not supported with auth_mechanism %q", c.AuthMechanism)
+	}
+
+	switch c.AuthMechanism {
+	case "":
+		return nil
+	case "SCRAM-SHA-1", "SCRAM-SHA-256", "PLAIN":
+		if c.Username == "" || c.Password == "" {
+			return fmt.Errorf("auth_mechanism %s requires username and password", c.AuthMechanism)
+		}
+	case "MONGODB-X509":
+		if c.ClientCertificate == "" {
+			return errors.New("auth_mechanism MONGODB-X509 requires client_certificate")
+		}
+		if c.Password != "" {
+			return errors.New("password must not be set with auth_mechanism MONGODB-X509")
+		}
+	case "MONGODB-AWS":
+		if (c.Username == "") != (c.Password == "") {
+			return errors.New("auth_mechanism MONGODB-AWS requires both username (access key ID) and password (secret access key), or neither")
+		}
+		for key := range c.AuthMechanismProperties {
+			if key != "AWS_SESSION_TOKEN" {
+				return fmt.Errorf("unsupported auth_mechanism_properties key %q for MONGODB-AWS, expected AWS_SESSION_TOKEN", key)
+			}
+		}
+		if c.AuthMechanismProperties["AWS_SESSION_TOKEN"] != "" && c.Username == "" {
+			return errors.New("AWS_SESSION_TOKEN requires username and password to be set")
+		}
+	default:
+		return fmt.Errorf("unsupported auth_mechanism %q", c.AuthMechanism)
+	}
+	return nil
+}
+
 func getTLSConfig(ca []byte, verify bool) (*tls.Config, error) {
 	/* As of version 1.2.1, the MongoDB Go Driver will only use the first CA server certificate found in sslcertificateauthorityfile.
 	   The code below addresses this limitation by manually appending all server certificates found in sslcertificateauthorityfile
@@ -169,29 +286,146 @@ func getTLSConfig(ca []byte, verify bool) (*tls.Config, error) {
 	return tlsConfig, nil
 }
 
+// loadClientCertificate builds the TLS client certificate from PEM content or
+// file paths. When key is empty the private key is expected to be bundled with
+// the certificate, as in a mongod PEMKeyFile.
+func loadClientCertificate(certificate string, key string, password string) (tls.Certificate, error) {
+	certPEM, err := readPEM(certificate)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("could not read client certificate : %s", err)
+	}
+	keyPEM := certPEM
+	if key != "" {
+		keyPEM, err = readPEM(key)
+		if err != nil {
+			return tls.Certificate{}, fmt.Errorf("could not read client certificate key : %s", err)
+		}
+	}
+
+	var certBlocks, keyBlocks []byte
+	for block, rest := pem.Decode(certPEM); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type == "CERTIFICATE" {
+			certBlocks = append(certBlocks, pem.EncodeToMemory(block)...)
+		}
+	}
+	for block, rest := pem.Decode(keyPEM); block != nil; block, rest = pem.Decode(rest) {
+		if !strings.HasSuffix(block.Type, "PRIVATE KEY") {
+			continue
+		}
+		decrypted, err := decryptPrivateKey(block, password)
+		if err != nil {
+			return tls.Certificate{}, err
+		}
+		keyBlocks = append(keyBlocks, pem.EncodeToMemory(decrypted)...)
+	}
+	if len(certBlocks) == 0 {
+		return tls.Certificate{}, errors.New("failed to find CERTIFICATE in client certificate")
+	}
+	if len(keyBlocks) == 0 {
+		return tls.Certificate{}, errors.New("failed to find PRIVATE KEY for client certificate")
+	}
+
+	return tls.X509KeyPair(certBlocks, keyBlocks)
+}
+
+func decryptPrivateKey(block *pem.Block, password string) (*pem.Block, error) {
+	//nolint:staticcheck // legacy DEK-Info encrypted keys are still produced by openssl
+	legacy := x509.IsEncryptedPEMBlock(block)
+	if !legacy && block.Type != "ENCRYPTED PRIVATE KEY" {
+		return block, nil
+	}
+	if password == "" {
+		return nil, errors.New("client certificate key is encrypted but no password was provided")
+	}
+
+	if legacy {
+		//nolint:staticcheck // see above
+		der, err := x509.DecryptPEMBlock(block, []byte(password))
+		if err != nil {
+			return nil, fmt.Errorf("could not decrypt client certificate key : %s", err)
+		}
+		return &pem.Block{Type: block.Type, Bytes: der}, nil
+	}
+
+	key, err := pkcs8.ParsePKCS8PrivateKey(block.Bytes, []byte(password))
+	if err != nil {
+		return nil, fmt.Errorf("could not decrypt client certificate key : %s", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return &pem.Block{Type: "PRIVATE KEY", Bytes: der}, nil
+}
+
+// readPEM returns value itself when it holds PEM content, otherwise it is read as a file path.
+func readPEM(value string) ([]byte, error) {
+	if strings.Contains(value, "-----BEGIN") {
+		return []byte(value), nil
+	}
+	content, err := os.ReadFile(value)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.TrimSpace(content), nil
+}
+
 func (privilege Privilege) String() string {
 	return fmt.Sprintf("{ resource : %s , actions : %s }", privilege.Resource, privilege.Actions)
 }
 
+// Resource is a privilege resource document. Only the fields of one kind are
+// set: { db, collection }, { db, system_buckets }, { cluster } or { anyResource }.
+// Empty db and collection names are meaningful, hence the pointers.
 type Resource struct {
-	Db         string `json:"db"`
-	Collection string `json:"collection"`
+	Db            *string `json:"db,omitempty" bson:"db,omitempty"`
+	Collection    *string `json:"collection,omitempty" bson:"collection,omitempty"`
+	SystemBuckets *string `json:"system_buckets,omitempty" bson:"system_buckets,omitempty"`
+	Cluster       bool    `json:"cluster,omitempty" bson:"cluster,omitempty"`
+	AnyResource   bool    `json:"anyResource,omitempty" bson:"anyResource,omitempty"`
 }
 
 func (resource Resource) String() string {
-	return fmt.Sprintf(" { db : %s , collection : %s }", resource.Db, resource.Collection)
+	switch {
+	case resource.Cluster:
+		return " { cluster : true }"
+	case resource.AnyResource:
+		return " { anyResource : true }"
+	case resource.SystemBuckets != nil:
+		return fmt.Sprintf(" { db : %s , system_buckets : %s }", stringValue(resource.Db), *resource.SystemBuckets)
+	}
+	return fmt.Sprintf(" { db : %s , collection : %s }", stringValue(resource.Db), stringValue(resource.Collection))
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
 }
 
 func createUser(client *mongo.Client, user DbUser, roles []Role, database string) error {
-	var result *mongo.SingleResult
+	command := bson.D{{Key: "createUser", Value: user.Name}}
+	// Users in $external authenticate outside of MongoDB and have no password
+	if user.Password != "" {
+		command = append(command, bson.E{Key: "pwd", Value: user.Password})
+	}
 	if len(roles) != 0 {
-		result = client.Database(database).RunCommand(context.Background(), bson.D{{Key: "createUser", Value: user.Name},
-			{Key: "pwd", Value: user.Password}, {Key: "roles", Value: roles}})
+		command = append(command, bson.E{Key: "roles", Value: roles})
 	} else {
-		result = client.Database(database).RunCommand(context.Background(), bson.D{{Key: "createUser", Value: user.Name},
-			{Key: "pwd", Value: user.Password}, {Key: "roles", Value: []bson.M{}}})
+		command = append(command, bson.E{Key: "roles", Value: []bson.M{}})
+	}
+	if len(user.Mechanisms) != 0 {
+		command = append(command, bson.E{Key: "mechanisms", Value: user.Mechanisms})
+	}
+	if len(user.CustomData) != 0 {
+		command = append(command, bson.E{Key: "customData", Value: user.CustomData})
+	}
+	if len(user.AuthenticationRestrictions) != 0 {
+		command = append(command, bson.E{Key: "authenticationRestrictions", Value: user.AuthenticationRestrictions})
 	}
 
+	result := client.Database(database).RunCommand(context.Background(), command)
 	if result.Err() != nil {
 		return result.Err()
 	}
@@ -203,7 +437,7 @@ func getUser(client *mongo.Client, username string, database string) (SingleResu
 		{Key: "user", Value: username},
 		{Key: "db", Value: database},
 	},
-	}})
+	}, {Key: "showCustomData", Value: true}, {Key: "showAuthenticationRestrictions", Value: true}})
 	var decodedResult SingleResultGetUser
 	err := result.Decode(&decodedResult)
 	if err != nil {
@@ -212,11 +446,98 @@ func getUser(client *mongo.Client, username string, database string) (SingleResu
 	return decodedResult, nil
 }
 
+// checkUserPassword authenticates as the user with a short-lived client and
+// reports whether the server accepted the password. Only an AuthenticationFailed
+// reply counts as a rejected password, any other error is returned as is.
+func checkUserPassword(conf *MongoDatabaseConfiguration, username string, password string, database string) (bool, error) {
+	userConfig := *conf.Config
+	userConfig.Username = username
+	userConfig.Password = password
+	userConfig.DB = database
+	userConfig.AuthMechanism = ""
+	userConfig.AuthMechanismProperties = nil
+
+	client, err := userConfig.MongoClient()
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = client.Disconnect(context.Background()) }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), conf.MaxConnLifetime*time.Second)
+	defer cancel()
+	err = client.Ping(ctx, nil)
+	var authError *auth.Error
+	var serverError driver.Error
+	if errors.As(err, &authError) && errors.As(authError, &serverError) && serverError.Code == authenticationFailedCode {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// hasInheritedAuthenticationRestrictions tells whether a role of the user
+// restricts where it can authenticate from. usersInfo only reports the
+// restrictions of the roles along with their privileges.
+func hasInheritedAuthenticationRestrictions(client *mongo.Client, username string, database string) (bool, error) {
+	var result struct {
+		Users []struct {
+			InheritedAuthenticationRestrictions bson.RawValue `json:"inheritedAuthenticationRestrictions" bson:"inheritedAuthenticationRestrictions"`
+		} `json:"users" bson:"users"`
+	}
+	err := client.Database(database).RunCommand(context.Background(), bson.D{{Key: "usersInfo", Value: bson.D{
+		{Key: "user", Value: username},
+		{Key: "db", Value: database},
+	}}, {Key: "showPrivileges", Value: true}, {Key: "showAuthenticationRestrictions", Value: true}}).Decode(&result)
+	if err != nil {
+		return false, err
+	}
+	for _, user := range result.Users {
+		restrictions, err := decodeAuthenticationRestrictions(user.InheritedAuthenticationRestrictions)
+		if err != nil {
+			return false, err
+		}
+		if len(restrictions) > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// decodeAuthenticationRestrictions flattens the authenticationRestrictions
+// returned by usersInfo and rolesInfo, which may nest restriction documents
+// in arrays.
+func decodeAuthenticationRestrictions(value bson.RawValue) ([]AuthenticationRestriction, error) {
+	var restrictions []AuthenticationRestriction
+	switch value.Type {
+	case bson.TypeArray:
+		elements, err := value.Array().Values()
+		if err != nil {
+			return nil, err
+		}
+		for _, element := range elements {
+			nested, err := decodeAuthenticationRestrictions(element)
+			if err != nil {
+				return nil, err
+			}
+			restrictions = append(restrictions, nested...)
+		}
+	case bson.TypeEmbeddedDocument:
+		var restriction AuthenticationRestriction
+		if err := bson.Unmarshal(value.Document(), &restriction); err != nil {
+			return nil, err
+		}
+		restrictions = append(restrictions, restriction)
+	}
+	return restrictions, nil
+}
+
 func getRole(client *mongo.Client, roleName string, database string) (SingleResultGetRole, error) {
 	result := client.Database(database).RunCommand(context.Background(), bson.D{{Key: "rolesInfo", Value: bson.D{
 		{Key: "role", Value: roleName},
 		{Key: "db", Value: database},
-	}}, {Key: "showPrivileges", Value: true}})
+	}}, {Key: "showPrivileges", Value: true}, {Key: "showAuthenticationRestrictions", Value: true}})
 	var decodedResult SingleResultGetRole
 	err := result.Decode(&decodedResult)
 	if err != nil {
@@ -225,32 +546,71 @@ func getRole(client *mongo.Client, roleName string, database string) (SingleResu
 	return decodedResult, nil
 }
 
-func createRole(client *mongo.Client, role string, roles []Role, privilege []PrivilegeDto, database string) error {
-	var privileges []Privilege
-	var result *mongo.SingleResult
+func toPrivileges(privilege []PrivilegeDto) []Privilege {
+	privileges := make([]Privilege, 0, len(privilege))
 	for _, element := range privilege {
 		var prv Privilege
-		prv.Resource = Resource{
-			Db:         element.Db,
-			Collection: element.Collection,
+		db, collection := element.Db, element.Collection
+		switch {
+		case element.Cluster:
+			prv.Resource = Resource{Cluster: true}
+		case element.AnyResource:
+			prv.Resource = Resource{AnyResource: true}
+		case element.SystemBuckets:
+			prv.Resource = Resource{Db: &db, SystemBuckets: &collection}
+		default:
+			prv.Resource = Resource{Db: &db, Collection: &collection}
 		}
 		prv.Actions = element.Actions
 		privileges = append(privileges, prv)
 	}
-	if len(roles) != 0 && len(privileges) != 0 {
-		result = client.Database(database).RunCommand(context.Background(), bson.D{{Key: "createRole", Value: role},
-			{Key: "privileges", Value: privileges}, {Key: "roles", Value: roles}})
-	} else if len(roles) == 0 && len(privileges) != 0 {
-		result = client.Database(database).RunCommand(context.Background(), bson.D{{Key: "createRole", Value: role},
-			{Key: "privileges", Value: privileges}, {Key: "roles", Value: []bson.M{}}})
-	} else if len(roles) != 0 && len(privileges) == 0 {
-		result = client.Database(database).RunCommand(context.Background(), bson.D{{Key: "createRole", Value: role},
-			{Key: "privileges", Value: []bson.M{}}, {Key: "roles", Value: roles}})
+	return privileges
+}
+
+func createRole(client *mongo.Client, role string, roles []Role, privilege []PrivilegeDto, restrictions []AuthenticationRestriction, database string) error {
+	privileges := toPrivileges(privilege)
+	command := bson.D{{Key: "createRole", Value: role}}
+	if len(privileges) != 0 {
+		command = append(command, bson.E{Key: "privileges", Value: privileges})
 	} else {
-		result = client.Database(database).RunCommand(context.Background(), bson.D{{Key: "createRole", Value: role},
-			{Key: "privileges", Value: []bson.M{}}, {Key: "roles", Value: []bson.M{}}})
+		command = append(command, bson.E{Key: "privileges", Value: []bson.M{}})
+	}
+	if len(roles) != 0 {
+		command = append(command, bson.E{Key: "roles", Value: roles})
+	} else {
+		command = append(command, bson.E{Key: "roles", Value: []bson.M{}})
+	}
+	if len(restrictions) != 0 {
+		command = append(command, bson.E{Key: "authenticationRestrictions", Value: restrictions})
+	}
+
+	result := client.Database(database).RunCommand(context.Background(), command)
+	if result.Err() != nil {
+		return result.Err()
+	}
+	return nil
+}
+
+// updateRole replaces the privileges, inherited roles and/or authentication
+// restrictions of a role in a single updateRole command, so users holding the
+// role never lose access while it changes. A nil slice leaves the
+// corresponding field untouched.
+func updateRole(client *mongo.Client, role string, roles []Role, privilege []PrivilegeDto, restrictions []AuthenticationRestriction, database string) error {
+	command := bson.D{{Key: "updateRole", Value: role}}
+	if privilege != nil {
+		command = append(command, bson.E{Key: "privileges", Value: toPrivileges(privilege)})
+	}
+	if roles != nil {
+		command = append(command, bson.E{Key: "roles", Value: roles})
+	}
+	if restrictions != nil {
+		command = append(command, bson.E{Key: "authenticationRestrictions", Value: restrictions})
+	}
+	if len(command) == 1 {
+		return nil
 	}
 
+	result := client.Database(database).RunCommand(context.Background(), command)
 	if result.Err() != nil {
 		return result.Err()
 	}
@@ -258,8 +618,28 @@ func createRole(client *mongo.Client, role string, roles []Role, privilege []Pri
 }
 
 func MongoClientInit(conf *MongoDatabaseConfiguration) (*mongo.Client, error) {
+	return conf.clientFor(conf.Config)
+}
 
-	client, err := conf.Config.MongoClient()
+// clientFor returns the pooled client for the given connection settings,
+// connecting and pinging it on first use. Clients are shared by every
+// resource operation and released by Disconnect.
+func (conf *MongoDatabaseConfiguration) clientFor(c *ClientConfig) (*mongo.Client, error) {
+	key, err := c.cacheKey()
+	if err != nil {
+		return nil, err
+	}
+
+	conf.clientsMu.Lock()
+	client, ok := conf.clients[key]
+	conf.clientsMu.Unlock()
+	if ok {
+		return client, nil
+	}
+
+	// Connect without holding the lock, so one unreachable server does not
+	// block operations on the others
+	client, err = c.MongoClient()
 	if err != nil {
 		return nil, err
 	}
@@ -268,11 +648,77 @@ func MongoClientInit(conf *MongoDatabaseConfiguration) (*mongo.Client, error) {
 	// client.Connect is deprecated, already connected by mongo.Connect above
 	err = client.Ping(ctx, nil)
 	if err != nil {
+		_ = client.Disconnect(context.Background())
 		return nil, err
 	}
+
+	conf.clientsMu.Lock()
+	defer conf.clientsMu.Unlock()
+	if existing, ok := conf.clients[key]; ok {
+		// Another operation connected first, keep its client
+		_ = client.Disconnect(context.Background())
+		return existing, nil
+	}
+	if conf.clients == nil {
+		conf.clients = make(map[string]*mongo.Client)
+	}
+	conf.clients[key] = client
 	return client, nil
 }
 
+// Disconnect closes every client opened through this configuration.
+func (conf *MongoDatabaseConfiguration) Disconnect(ctx context.Context) error {
+	conf.clientsMu.Lock()
+	defer conf.clientsMu.Unlock()
+
+	var errs []error
+	for key, client := range conf.clients {
+		if err := client.Disconnect(ctx); err != nil {
+			errs = append(errs, err)
+		}
+		delete(conf.clients, key)
+	}
+	return errors.Join(errs...)
+}
+
+// cacheKey identifies the effective connection settings of a client. The
+// settings are hashed to keep the credentials out of the key.
+func (c *ClientConfig) cacheKey() (string, error) {
+	settings, err := json.Marshal(c)
+	if err != nil {
+		return "", fmt.Errorf("could not build client cache key : %s", err)
+	}
+	sum := sha256.Sum256(settings)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+var (
+	configurationsMu sync.Mutex
+	configurations   []*MongoDatabaseConfiguration
+)
+
+func registerConfiguration(conf *MongoDatabaseConfiguration) {
+	configurationsMu.Lock()
+	defer configurationsMu.Unlock()
+	configurations = append(configurations, conf)
+}
+
+// DisconnectClients closes the clients of every provider configured in this
+// process. It is meant to be called once the plugin server has stopped.
+func DisconnectClients(ctx context.Context) error {
+	configurationsMu.Lock()
+	defer configurationsMu.Unlock()
+
+	var errs []error
+	for _, conf := range configurations {
+		if err := conf.Disconnect(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	configurations = nil
+	return errors.Join(errs...)
+}
+
 func proxyDialer(c *ClientConfig) (options.ContextDialer, error) {
 	proxyFromEnv := proxy.FromEnvironment().(options.ContextDialer)
 	proxyFromProvider := c.Proxy
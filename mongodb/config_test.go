@@ -0,0 +1,114 @@
+package mongodb
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/youmark/pkcs8"
+)
+
+func TestLoadClientCertificate(t *testing.T) {
+	const password = "secret"
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("error generating key: %s", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("error creating certificate: %s", err)
+	}
+	certPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}))
+
+	plainDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("error encoding key: %s", err)
+	}
+	plainPEM := string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: plainDER}))
+
+	encryptedDER, err := pkcs8.ConvertPrivateKeyToPKCS8(key, []byte(password))
+	if err != nil {
+		t.Fatalf("error encrypting key: %s", err)
+	}
+	encryptedPEM := string(pem.EncodeToMemory(&pem.Block{Type: "ENCRYPTED PRIVATE KEY", Bytes: encryptedDER}))
+
+	ecDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("error encoding key: %s", err)
+	}
+	//nolint:staticcheck // legacy DEK-Info encrypted keys are still produced by openssl
+	legacyBlock, err := x509.EncryptPEMBlock(rand.Reader, "EC PRIVATE KEY", ecDER, []byte(password), x509.PEMCipherAES256)
+	if err != nil {
+		t.Fatalf("error encrypting key: %s", err)
+	}
+	legacyPEM := string(pem.EncodeToMemory(legacyBlock))
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "client.crt")
+	keyFile := filepath.Join(dir, "client.key")
+	if err := os.WriteFile(certFile, []byte(certPEM), 0600); err != nil {
+		t.Fatalf("error writing certificate: %s", err)
+	}
+	if err := os.WriteFile(keyFile, []byte(encryptedPEM), 0600); err != nil {
+		t.Fatalf("error writing key: %s", err)
+	}
+
+	cases := []struct {
+		name        string
+		certificate string
+		key         string
+		password    string
+		expectError *regexp.Regexp
+	}{
+		{name: "plain", certificate: certPEM, key: plainPEM},
+		{name: "pkcs8 encrypted", certificate: certPEM, key: encryptedPEM, password: password},
+		{name: "legacy encrypted", certificate: certPEM, key: legacyPEM, password: password},
+		{name: "bundled", certificate: certPEM + plainPEM},
+		{name: "bundled encrypted", certificate: certPEM + legacyPEM, password: password},
+		{name: "file paths", certificate: certFile, key: keyFile, password: password},
+		{name: "pkcs8 wrong password", certificate: certPEM, key: encryptedPEM, password: "wrong", expectError: regexp.MustCompile("could not decrypt")},
+		// DEK-Info decryption only checks the padding, a wrong password may
+		// yield a key that fails to parse instead
+		{name: "legacy wrong password", certificate: certPEM, key: legacyPEM, password: "wrong", expectError: regexp.MustCompile("could not decrypt|private key")},
+		{name: "pkcs8 missing password", certificate: certPEM, key: encryptedPEM, expectError: regexp.MustCompile("no password was provided")},
+		{name: "legacy missing password", certificate: certPEM, key: legacyPEM, expectError: regexp.MustCompile("no password was provided")},
+		{name: "missing key", certificate: certPEM, expectError: regexp.MustCompile("failed to find PRIVATE KEY")},
+		{name: "missing file", certificate: filepath.Join(dir, "missing.crt"), key: keyFile, expectError: regexp.MustCompile("could not read client certificate")},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			certificate, err := loadClientCertificate(tc.certificate, tc.key, tc.password)
+			if tc.expectError != nil {
+				if err == nil || !tc.expectError.MatchString(err.Error()) {
+					t.Fatalf("expected an error matching %q, got %v", tc.expectError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if len(certificate.Certificate) != 1 || !bytes.Equal(certificate.Certificate[0], certDER) {
+				t.Fatalf("expected the generated certificate to be loaded")
+			}
+		})
+	}
+}
@@ -2,11 +2,31 @@ package mongodb
 
 import (
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"reflect"
+	"strings"
+
 	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"strings"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// Server error codes the provider tolerates.
+const (
+	authenticationFailedCode = 18
+	namespaceNotFoundCode    = 26
+	indexNotFoundCode        = 27
+	namespaceExistsCode      = 48
+	// indexOptionsConflictCode is returned when an index with the same key
+	// pattern exists under another name or with other options.
+	indexOptionsConflictCode = 85
+	// indexKeySpecsConflictCode is returned when an index with the same name
+	// has another key pattern.
+	indexKeySpecsConflictCode = 86
 )
 
 func validateDiagFunc(validateFunc func(interface{}, string) ([]string, []error)) schema.SchemaValidateDiagFunc {
@@ -53,3 +73,239 @@ func SetId(data *schema.ResourceData, parts []string) {
 	encoded := base64.StdEncoding.EncodeToString([]byte(id))
 	data.SetId(encoded)
 }
+
+// suppressEquivalentJSON ignores formatting and key order differences between two JSON documents.
+func suppressEquivalentJSON(k, old, new string, d *schema.ResourceData) bool {
+	if old == new {
+		return true
+	}
+	var oldValue, newValue interface{}
+	if json.Unmarshal([]byte(old), &oldValue) != nil || json.Unmarshal([]byte(new), &newValue) != nil {
+		return false
+	}
+	return reflect.DeepEqual(oldValue, newValue)
+}
+
+// normalizeExtJSON re-encodes an Extended JSON document as relaxed Extended
+// JSON, the form Read stores server documents in.
+func normalizeExtJSON(value string) (string, error) {
+	var document bson.D
+	if err := bson.UnmarshalExtJSON([]byte(value), false, &document); err != nil {
+		return "", err
+	}
+	normalized, err := bson.MarshalExtJSON(document, false, false)
+	if err != nil {
+		return "", err
+	}
+	return string(normalized), nil
+}
+
+// suppressEquivalentExtJSON is suppressEquivalentJSON for Extended JSON,
+// where e.g. {"$numberInt": "1"} and 1 are the same value.
+func suppressEquivalentExtJSON(k, old, new string, d *schema.ResourceData) bool {
+	if old == new {
+		return true
+	}
+	oldValue, oldErr := normalizeExtJSON(old)
+	newValue, newErr := normalizeExtJSON(new)
+	if oldErr != nil || newErr != nil {
+		return false
+	}
+	return suppressEquivalentJSON(k, oldValue, newValue, d)
+}
+
+func validateExtJSONDocument(i interface{}, k string) ([]string, []error) {
+	value, ok := i.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+	}
+	if value == "" {
+		return nil, nil
+	}
+	if _, err := normalizeExtJSON(value); err != nil {
+		return nil, []error{fmt.Errorf("%q contains an invalid Extended JSON document: %s", k, err)}
+	}
+	return nil, nil
+}
+
+func authenticationRestrictionSchema() *schema.Schema {
+	addresses := &schema.Schema{
+		Type:     schema.TypeSet,
+		Optional: true,
+		Elem: &schema.Schema{
+			Type:             schema.TypeString,
+			ValidateDiagFunc: validateDiagFunc(validation.Any(validation.IsIPAddress, validation.IsCIDR)),
+		},
+	}
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"client_source":  addresses,
+				"server_address": addresses,
+			},
+		},
+	}
+}
+
+func expandAuthenticationRestrictions(restrictions []interface{}) []AuthenticationRestriction {
+	result := make([]AuthenticationRestriction, 0, len(restrictions))
+	for _, element := range restrictions {
+		var restriction AuthenticationRestriction
+		if values, ok := element.(map[string]interface{}); ok {
+			for _, source := range values["client_source"].(*schema.Set).List() {
+				restriction.ClientSource = append(restriction.ClientSource, source.(string))
+			}
+			for _, address := range values["server_address"].(*schema.Set).List() {
+				restriction.ServerAddress = append(restriction.ServerAddress, address.(string))
+			}
+		}
+		result = append(result, restriction)
+	}
+	return result
+}
+
+func flattenAuthenticationRestrictions(restrictions []AuthenticationRestriction) []interface{} {
+	result := make([]interface{}, len(restrictions))
+	for i, restriction := range restrictions {
+		result[i] = map[string]interface{}{
+			"client_source":  restriction.ClientSource,
+			"server_address": restriction.ServerAddress,
+		}
+	}
+	return result
+}
+
+// collationSchema is the collation block shared by collections and indexes.
+// Only the locale is required, the server fills in the other fields from the
+// locale's defaults and reports them back. A computed block is inherited from
+// the collection when it is not configured.
+func collationSchema(computed bool) *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		Computed: computed,
+		ForceNew: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"locale": {
+					Type:     schema.TypeString,
+					Required: true,
+					ForceNew: true,
+				},
+				"case_level": {
+					Type:     schema.TypeBool,
+					Optional: true,
+					Computed: true,
+					ForceNew: true,
+				},
+				"case_first": {
+					Type:             schema.TypeString,
+					Optional:         true,
+					Computed:         true,
+					ForceNew:         true,
+					ValidateDiagFunc: validateDiagFunc(validation.StringInSlice([]string{"upper", "lower", "off"}, false)),
+				},
+				"strength": {
+					Type:             schema.TypeInt,
+					Optional:         true,
+					Computed:         true,
+					ForceNew:         true,
+					ValidateDiagFunc: validateDiagFunc(validation.IntBetween(1, 5)),
+				},
+				"numeric_ordering": {
+					Type:     schema.TypeBool,
+					Optional: true,
+					Computed: true,
+					ForceNew: true,
+				},
+				"alternate": {
+					Type:             schema.TypeString,
+					Optional:         true,
+					Computed:         true,
+					ForceNew:         true,
+					ValidateDiagFunc: validateDiagFunc(validation.StringInSlice([]string{"non-ignorable", "shifted"}, false)),
+				},
+				"max_variable": {
+					Type:             schema.TypeString,
+					Optional:         true,
+					Computed:         true,
+					ForceNew:         true,
+					ValidateDiagFunc: validateDiagFunc(validation.StringInSlice([]string{"punct", "space"}, false)),
+				},
+				"normalization": {
+					Type:     schema.TypeBool,
+					Optional: true,
+					Computed: true,
+					ForceNew: true,
+				},
+				"backwards": {
+					Type:     schema.TypeBool,
+					Optional: true,
+					Computed: true,
+					ForceNew: true,
+				},
+			},
+		},
+	}
+}
+
+func expandCollation(collation []interface{}) *options.Collation {
+	if len(collation) == 0 || collation[0] == nil {
+		return nil
+	}
+	values := collation[0].(map[string]interface{})
+	return &options.Collation{
+		Locale:          values["locale"].(string),
+		CaseLevel:       values["case_level"].(bool),
+		CaseFirst:       values["case_first"].(string),
+		Strength:        values["strength"].(int),
+		NumericOrdering: values["numeric_ordering"].(bool),
+		Alternate:       values["alternate"].(string),
+		MaxVariable:     values["max_variable"].(string),
+		Normalization:   values["normalization"].(bool),
+		Backwards:       values["backwards"].(bool),
+	}
+}
+
+// flattenCollation reads the collation document of listCollections and
+// listIndexes. The server omits the simple collation, so a configured one is
+// kept as it is.
+func flattenCollation(document bson.Raw, current []interface{}) []interface{} {
+	if document == nil {
+		if collation := expandCollation(current); collation != nil && collation.Locale == "simple" {
+			return current
+		}
+		return nil
+	}
+	locale, _ := document.Lookup("locale").StringValueOK()
+	caseLevel, _ := document.Lookup("caseLevel").BooleanOK()
+	caseFirst, _ := document.Lookup("caseFirst").StringValueOK()
+	strength, _ := document.Lookup("strength").AsInt64OK()
+	numericOrdering, _ := document.Lookup("numericOrdering").BooleanOK()
+	alternate, _ := document.Lookup("alternate").StringValueOK()
+	maxVariable, _ := document.Lookup("maxVariable").StringValueOK()
+	normalization, _ := document.Lookup("normalization").BooleanOK()
+	backwards, _ := document.Lookup("backwards").BooleanOK()
+	return []interface{}{map[string]interface{}{
+		"locale":           locale,
+		"case_level":       caseLevel,
+		"case_first":       caseFirst,
+		"strength":         int(strength),
+		"numeric_ordering": numericOrdering,
+		"alternate":        alternate,
+		"max_variable":     maxVariable,
+		"normalization":    normalization,
+		"backwards":        backwards,
+	}}
+}
+
+func expandStringSet(set *schema.Set) []string {
+	result := make([]string, 0, set.Len())
+	for _, value := range set.List() {
+		result = append(result, value.(string))
+	}
+	return result
+}
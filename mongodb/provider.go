@@ -3,11 +3,13 @@ package mongodb
 import (
 	"context"
 	"regexp"
+	"sync"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
 func Provider() *schema.Provider {
@@ -37,6 +39,26 @@ func Provider() *schema.Provider {
 				DefaultFunc: schema.EnvDefaultFunc("MONGODB_CERT", ""),
 				Description: "PEM-encoded content of Mongodb host CA certificate",
 			},
+			"client_certificate": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("MONGODB_CLIENT_CERT", ""),
+				Description: "PEM-encoded content or path of the client certificate used for TLS and MONGODB-X509 authentication",
+			},
+			"client_certificate_key": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc("MONGODB_CLIENT_KEY", ""),
+				Description: "PEM-encoded content or path of the client certificate private key, if not bundled with client_certificate",
+			},
+			"client_certificate_key_password": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc("MONGODB_CLIENT_KEY_PASSWORD", ""),
+				Description: "Passphrase of an encrypted client certificate private key",
+			},
 			"username": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -56,6 +78,22 @@ func Provider() *schema.Provider {
 				Default:     "admin",
 				Description: "The mongodb auth database",
 			},
+			"auth_mechanism": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "",
+				Description:      "The authentication mechanism, negotiated by the driver when empty",
+				ValidateDiagFunc: validateDiagFunc(validation.StringInSlice([]string{"", "SCRAM-SHA-1", "SCRAM-SHA-256", "PLAIN", "MONGODB-X509", "MONGODB-AWS"}, false)),
+			},
+			"auth_mechanism_properties": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Sensitive:   true,
+				Description: "Additional properties of the authentication mechanism, e.g. AWS_SESSION_TOKEN for MONGODB-AWS",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
 			"replica_set": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -97,10 +135,13 @@ func Provider() *schema.Provider {
 			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"mongodb_db_user":       resourceDatabaseUser(),
-			"mongodb_db_role":       resourceDatabaseRole(),
-			"mongodb_db_collection": resourceDatabaseCollection(),
-			"mongodb_db_index":      resourceDatabaseIndex(),
+			"mongodb_database":             resourceDatabase(),
+			"mongodb_db_user":              resourceDatabaseUser(),
+			"mongodb_db_role":              resourceDatabaseRole(),
+			"mongodb_db_collection":        resourceDatabaseCollection(),
+			"mongodb_db_index":             resourceDatabaseIndex(),
+			"mongodb_db_view":              resourceDatabaseView(),
+			"mongodb_db_materialized_view": resourceDatabaseMaterializedView(),
 		},
 		DataSourcesMap:       map[string]*schema.Resource{},
 		ConfigureContextFunc: providerConfigure,
@@ -110,6 +151,9 @@ func Provider() *schema.Provider {
 type MongoDatabaseConfiguration struct {
 	Config          *ClientConfig
 	MaxConnLifetime time.Duration
+
+	clientsMu sync.Mutex
+	clients   map[string]*mongo.Client
 }
 
 func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
@@ -129,10 +173,28 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 		Direct:             d.Get("direct").(bool),
 		RetryWrites:        d.Get("retrywrites").(bool),
 		Proxy:              d.Get("proxy").(string),
+
+		ClientCertificate:            d.Get("client_certificate").(string),
+		ClientCertificateKey:         d.Get("client_certificate_key").(string),
+		ClientCertificateKeyPassword: d.Get("client_certificate_key_password").(string),
+
+		AuthMechanism: d.Get("auth_mechanism").(string),
+	}
+	if properties := d.Get("auth_mechanism_properties").(map[string]interface{}); len(properties) > 0 {
+		clientConfig.AuthMechanismProperties = make(map[string]string, len(properties))
+		for key, value := range properties {
+			clientConfig.AuthMechanismProperties[key] = value.(string)
+		}
+	}
+	if err := clientConfig.validateAuth(); err != nil {
+		return nil, diag.FromErr(err)
 	}
 
-	return &MongoDatabaseConfiguration{
+	conf := &MongoDatabaseConfiguration{
 		Config:          &clientConfig,
 		MaxConnLifetime: 10,
-	}, diags
+	}
+	registerConfiguration(conf)
+
+	return conf, diags
 }
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
 var testAccProviderFactories map[string]func() (*schema.Provider, error)
@@ -45,9 +46,137 @@ func testAccPreCheck(t *testing.T) {
 	}
 }
 
+// testAccClientConfig configures the provider with the same settings as
+// testAccPreCheck, for tests and test steps running before the provider has
+// been configured by Terraform.
+func testAccClientConfig(t *testing.T) *MongoDatabaseConfiguration {
+	d := schema.TestResourceDataRaw(t, testAccProvider.Schema, map[string]interface{}{
+		"host":          getEnvWithDefault("MONGO_HOST", "127.0.0.1"),
+		"port":          getEnvWithDefault("MONGO_PORT", "27017"),
+		"username":      getEnvWithDefault("MONGO_USR", "root"),
+		"password":      getEnvWithDefault("MONGO_PWD", "root"),
+		"auth_database": getEnvWithDefault("MONGO_AUTH_DB", "admin"),
+	})
+	meta, diags := testAccProvider.ConfigureContextFunc(context.Background(), d)
+	if diags.HasError() {
+		t.Fatalf("Failed to configure provider: %v", diags)
+	}
+	return meta.(*MongoDatabaseConfiguration)
+}
+
+// testAccClient connects with the settings of testAccClientConfig.
+func testAccClient(t *testing.T) *mongo.Client {
+	client, err := MongoClientInit(testAccClientConfig(t))
+	if err != nil {
+		t.Fatalf("error connecting to database: %s", err)
+	}
+	return client
+}
+
 func getEnvWithDefault(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
 	}
 	return defaultValue
-}
\ No newline at end of file
+}
+
+func TestAccProvider_SharedClient(t *testing.T) {
+	if os.Getenv("TF_ACC") != "1" {
+		t.Skip("acceptance tests skipped unless env 'TF_ACC' set")
+	}
+
+	config := testAccClientConfig(t)
+
+	first, err := MongoClientInit(config)
+	if err != nil {
+		t.Fatalf("error connecting to database: %s", err)
+	}
+	second, err := MongoClientInit(config)
+	if err != nil {
+		t.Fatalf("error connecting to database: %s", err)
+	}
+	if first != second {
+		t.Fatalf("expected the pooled client to be reused")
+	}
+
+	if err := config.Disconnect(context.Background()); err != nil {
+		t.Fatalf("error disconnecting clients: %s", err)
+	}
+	third, err := MongoClientInit(config)
+	if err != nil {
+		t.Fatalf("error reconnecting to database: %s", err)
+	}
+	if third == first {
+		t.Fatalf("expected a new client after Disconnect")
+	}
+	_ = config.Disconnect(context.Background())
+}
+
+func TestAccProvider_X509(t *testing.T) {
+	if os.Getenv("TF_ACC") != "1" {
+		t.Skip("acceptance tests skipped unless env 'TF_ACC' set")
+	}
+	clientCertificate := os.Getenv("MONGODB_CLIENT_CERT")
+	if clientCertificate == "" {
+		t.Skip("MONGODB_CLIENT_CERT not set, see docker/docker-mongo-ssl/scripts/gen_client_cert.sh")
+	}
+
+	d := schema.TestResourceDataRaw(t, testAccProvider.Schema, map[string]interface{}{
+		"host":                 getEnvWithDefault("MONGO_HOST", "127.0.0.1"),
+		"port":                 getEnvWithDefault("MONGO_PORT", "27017"),
+		"insecure_skip_verify": true,
+		"client_certificate":   clientCertificate,
+	})
+	meta, diags := testAccProvider.ConfigureContextFunc(context.Background(), d)
+	if diags.HasError() {
+		t.Fatalf("Failed to configure provider: %v", diags)
+	}
+	config := meta.(*MongoDatabaseConfiguration)
+	defer func() { _ = config.Disconnect(context.Background()) }()
+
+	// Drop SCRAM credentials sourced from MONGO_USR / MONGO_PWD
+	config.Config.Username = ""
+	config.Config.Password = ""
+
+	if _, err := MongoClientInit(config); err != nil {
+		t.Fatalf("error connecting with X.509 client certificate: %s", err)
+	}
+}
+
+func TestClientConfigValidateAuth(t *testing.T) {
+	cases := []struct {
+		name    string
+		config  ClientConfig
+		wantErr bool
+	}{
+		{"negotiated", ClientConfig{Username: "root", Password: "root"}, false},
+		{"scram sha 256", ClientConfig{AuthMechanism: "SCRAM-SHA-256", Username: "root", Password: "root"}, false},
+		{"scram without password", ClientConfig{AuthMechanism: "SCRAM-SHA-256", Username: "root"}, true},
+		{"plain", ClientConfig{AuthMechanism: "PLAIN", Username: "ldap-user", Password: "secret"}, false},
+		{"plain with properties", ClientConfig{AuthMechanism: "PLAIN", Username: "ldap-user", Password: "secret",
+			AuthMechanismProperties: map[string]string{"AWS_SESSION_TOKEN": "token"}}, true},
+		{"x509", ClientConfig{AuthMechanism: "MONGODB-X509", ClientCertificate: "client.pem"}, false},
+		{"x509 without certificate", ClientConfig{AuthMechanism: "MONGODB-X509"}, true},
+		{"x509 with password", ClientConfig{AuthMechanism: "MONGODB-X509", ClientCertificate: "client.pem", Password: "root"}, true},
+		{"aws from environment", ClientConfig{AuthMechanism: "MONGODB-AWS"}, false},
+		{"aws with session token", ClientConfig{AuthMechanism: "MONGODB-AWS", Username: "AKIA", Password: "secret",
+			AuthMechanismProperties: map[string]string{"AWS_SESSION_TOKEN": "token"}}, false},
+		{"aws with access key only", ClientConfig{AuthMechanism: "MONGODB-AWS", Username: "AKIA"}, true},
+		{"aws session token without keys", ClientConfig{AuthMechanism: "MONGODB-AWS",
+			AuthMechanismProperties: map[string]string{"AWS_SESSION_TOKEN": "token"}}, true},
+		{"aws unknown property", ClientConfig{AuthMechanism: "MONGODB-AWS", Username: "AKIA", Password: "secret",
+			AuthMechanismProperties: map[string]string{"SERVICE_NAME": "mongodb"}}, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.config.validateAuth()
+			if tc.wantErr && err == nil {
+				t.Fatalf("expected an error")
+			}
+			if !tc.wantErr && err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+		})
+	}
+}
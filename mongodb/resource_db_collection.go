@@ -3,11 +3,15 @@ package mongodb
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
 func resourceDatabaseCollection() *schema.Resource {
@@ -16,19 +20,20 @@ func resourceDatabaseCollection() *schema.Resource {
 		ReadContext:   resourceDatabaseCollectionRead,
 		UpdateContext: resourceDatabaseCollectionUpdate,
 		DeleteContext: resourceDatabaseCollectionDelete,
+		CustomizeDiff: resourceDatabaseCollectionCustomizeDiff,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
 		Schema: map[string]*schema.Schema{
 			"db": {
-				Type:     schema.TypeString,
-				ForceNew: true,
-				Required: true,
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Changing it moves the collection to the new database with renameCollection",
 			},
 			"name": {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Changing it renames the collection in place with renameCollection",
 			},
 			"deletion_protection": {
 				Type:     schema.TypeBool,
@@ -45,6 +50,113 @@ func resourceDatabaseCollection() *schema.Resource {
 				Optional: true,
 				Default:  false,
 			},
+			"validator": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Description:      "Extended JSON query document, e.g. a $jsonSchema, documents must match",
+				ValidateDiagFunc: validateDiagFunc(validateExtJSONDocument),
+				DiffSuppressFunc: suppressEquivalentExtJSON,
+			},
+			"validation_level": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "strict",
+				ValidateDiagFunc: validateDiagFunc(validation.StringInSlice([]string{"off", "strict", "moderate"}, false)),
+			},
+			"validation_action": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "error",
+				ValidateDiagFunc: validateDiagFunc(validation.StringInSlice([]string{"error", "warn", "errorAndLog"}, false)),
+			},
+			"timeseries": {
+				Type:     schema.TypeList,
+				Optional: true,
+				// Computed to plan the bucket spans of a new granularity, see
+				// resourceDatabaseCollectionCustomizeDiff
+				Computed:    true,
+				MaxItems:    1,
+				Description: "Create a time series collection",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"time_field": {
+							Type:     schema.TypeString,
+							Required: true,
+							ForceNew: true,
+						},
+						"meta_field": {
+							Type:     schema.TypeString,
+							Optional: true,
+							ForceNew: true,
+						},
+						"granularity": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							Computed:         true,
+							ConflictsWith:    []string{"timeseries.0.bucket_max_span_seconds"},
+							ValidateDiagFunc: validateDiagFunc(validation.StringInSlice(timeseriesGranularities, false)),
+						},
+						"bucket_max_span_seconds": {
+							Type:             schema.TypeInt,
+							Optional:         true,
+							Computed:         true,
+							RequiredWith:     []string{"timeseries.0.bucket_rounding_seconds"},
+							ValidateDiagFunc: validateDiagFunc(validation.IntBetween(1, 31536000)),
+						},
+						"bucket_rounding_seconds": {
+							Type:             schema.TypeInt,
+							Optional:         true,
+							Computed:         true,
+							RequiredWith:     []string{"timeseries.0.bucket_max_span_seconds"},
+							ValidateDiagFunc: validateDiagFunc(validation.IntBetween(1, 31536000)),
+						},
+					},
+				},
+			},
+			"expire_after_seconds": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Description:      "Remove documents this many seconds after their time field or clustered _id, 0 keeps them forever",
+				ValidateDiagFunc: validateDiagFunc(validation.IntAtLeast(0)),
+			},
+			"capped": {
+				Type:          schema.TypeBool,
+				Optional:      true,
+				ForceNew:      true,
+				Default:       false,
+				ConflictsWith: []string{"timeseries", "clustered_index"},
+			},
+			"size": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Description:      "Maximum size in bytes of a capped collection",
+				ValidateDiagFunc: validateDiagFunc(validation.IntAtLeast(1)),
+				DiffSuppressFunc: suppressCappedSize,
+			},
+			"max": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Description:      "Maximum number of documents of a capped collection, 0 for no limit",
+				ValidateDiagFunc: validateDiagFunc(validation.IntAtLeast(0)),
+			},
+			"collation": collationSchema(false),
+			"clustered_index": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				MaxItems:    1,
+				Description: "Create a clustered collection, ordered by _id",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Computed: true,
+							ForceNew: true,
+						},
+					},
+				},
+			},
 		},
 	}
 }
@@ -62,7 +174,45 @@ func resourceDatabaseCollectionCreate(ctx context.Context, data *schema.Resource
 
 	dbClient := client.Database(db)
 
-	err := dbClient.CreateCollection(context.Background(), collectionName)
+	createOptions := options.CreateCollection()
+	validator, err := parseValidator(data.Get("validator").(string))
+	if err != nil {
+		return diag.Errorf("%s", err)
+	}
+	if validator != nil {
+		createOptions.SetValidator(validator)
+	}
+	if validator != nil || data.Get("validation_level").(string) != "strict" || data.Get("validation_action").(string) != "error" {
+		createOptions.SetValidationLevel(data.Get("validation_level").(string))
+		createOptions.SetValidationAction(data.Get("validation_action").(string))
+	}
+
+	if timeseries, ok := data.GetOk("timeseries.0"); ok {
+		createOptions.SetTimeSeriesOptions(expandTimeseries(timeseries.(map[string]interface{})))
+	}
+	if data.Get("capped").(bool) {
+		createOptions.SetCapped(true)
+		createOptions.SetSizeInBytes(int64(data.Get("size").(int)))
+		if maxDocuments := data.Get("max").(int); maxDocuments != 0 {
+			createOptions.SetMaxDocuments(int64(maxDocuments))
+		}
+	}
+	// The presence of the block is what matters, it may have no attributes set
+	if data.Get("clustered_index.#").(int) != 0 {
+		clusteredIndex := bson.D{{Key: "key", Value: bson.D{{Key: "_id", Value: 1}}}, {Key: "unique", Value: true}}
+		if name := data.Get("clustered_index.0.name").(string); name != "" {
+			clusteredIndex = append(clusteredIndex, bson.E{Key: "name", Value: name})
+		}
+		createOptions.SetClusteredIndex(clusteredIndex)
+	}
+	if collation := expandCollation(data.Get("collation").([]interface{})); collation != nil {
+		createOptions.SetCollation(collation)
+	}
+	if expireAfterSeconds := data.Get("expire_after_seconds").(int); expireAfterSeconds != 0 {
+		createOptions.SetExpireAfterSeconds(int64(expireAfterSeconds))
+	}
+
+	err = dbClient.CreateCollection(context.Background(), collectionName, createOptions)
 	if err != nil {
 		return diag.Errorf("Could not create the collection : %s ", err)
 	}
@@ -123,30 +273,110 @@ func resourceDatabaseCollectionRead(ctx context.Context, data *schema.ResourceDa
 		}
 	}
 
+	// collMod leaves an empty validator behind when it is removed
+	validator := ""
+	if document, ok := collectionSpec.Options.Lookup("validator").DocumentOK(); ok && !isEmptyDocument(document) {
+		validatorBytes, err := bson.MarshalExtJSON(document, false, false)
+		if err != nil {
+			return diag.Errorf("Failed to encode validator : %s ", err)
+		}
+		validator = string(validatorBytes)
+	}
+	validationLevel, ok := collectionSpec.Options.Lookup("validationLevel").StringValueOK()
+	if !ok {
+		validationLevel = "strict"
+	}
+	validationAction, ok := collectionSpec.Options.Lookup("validationAction").StringValueOK()
+	if !ok {
+		validationAction = "error"
+	}
+
+	var timeseries []interface{}
+	if document, ok := collectionSpec.Options.Lookup("timeseries").DocumentOK(); ok {
+		timeseries = append(timeseries, flattenTimeseries(document))
+	}
+	expireAfterSeconds, _ := collectionSpec.Options.Lookup("expireAfterSeconds").AsInt64OK()
+	capped, _ := collectionSpec.Options.Lookup("capped").BooleanOK()
+	size, _ := collectionSpec.Options.Lookup("size").AsInt64OK()
+	maxDocuments, _ := collectionSpec.Options.Lookup("max").AsInt64OK()
+	collation, _ := collectionSpec.Options.Lookup("collation").DocumentOK()
+	var clusteredIndex []interface{}
+	if document, ok := collectionSpec.Options.Lookup("clusteredIndex").DocumentOK(); ok {
+		name, _ := document.Lookup("name").StringValueOK()
+		clusteredIndex = append(clusteredIndex, map[string]interface{}{"name": name})
+	}
+
 	_ = data.Set("db", db)
 	_ = data.Set("name", collectionName)
 	_ = data.Set("deletion_protection", data.Get("deletion_protection").(bool))
 	// _ = data.Set("record_pre_images", recordPreImages)
 	_ = data.Set("change_stream_pre_and_post_images", changeStreamEnabled)
+	_ = data.Set("validator", validator)
+	_ = data.Set("validation_level", validationLevel)
+	_ = data.Set("validation_action", validationAction)
+	_ = data.Set("timeseries", timeseries)
+	_ = data.Set("expire_after_seconds", int(expireAfterSeconds))
+	_ = data.Set("capped", capped)
+	_ = data.Set("size", int(size))
+	_ = data.Set("max", int(maxDocuments))
+	_ = data.Set("clustered_index", clusteredIndex)
+	_ = data.Set("collation", flattenCollation(collation, data.Get("collation").([]interface{})))
 	return nil
 }
 
 func resourceDatabaseCollectionUpdate(ctx context.Context, data *schema.ResourceData, i interface{}) diag.Diagnostics {
-	dbClient, _, collectionName, err := parseDbAndCollection(data, i)
+	dbClient, db, collectionName, err := parseDbAndCollection(data, i)
 	if err != nil {
 		return diag.Errorf("%s", err)
 	}
 
+	// Rename first so every following collMod runs against the new namespace
+	if data.HasChanges("db", "name") {
+		var newDb = data.Get("db").(string)
+		var newCollectionName = data.Get("name").(string)
+		_err := renameCollection(dbClient.Client(), db, collectionName, newDb, newCollectionName)
+		if _err != nil {
+			return _err
+		}
+		SetId(data, []string{newDb, newCollectionName})
+		dbClient, collectionName = dbClient.Client().Database(newDb), newCollectionName
+	}
+
 	// var recordPreImages = data.Get("record_pre_images").(bool)
 	// _err := setPreRecordImages(dbClient, collectionName, recordPreImages)
 	// if _err != nil {
 	// 	return _err
 	// }
 
-	var changeStreamPreAndPostImages = data.Get("change_stream_pre_and_post_images").(bool)
-	_err := setChangeStreamPreAndPostImages(dbClient, collectionName, changeStreamPreAndPostImages)
-	if _err != nil {
-		return _err
+	// time series collections reject changeStreamPreAndPostImages, so it is
+	// only sent when it changes
+	if data.HasChange("change_stream_pre_and_post_images") {
+		var changeStreamPreAndPostImages = data.Get("change_stream_pre_and_post_images").(bool)
+		_err := setChangeStreamPreAndPostImages(dbClient, collectionName, changeStreamPreAndPostImages)
+		if _err != nil {
+			return _err
+		}
+	}
+
+	if data.HasChanges("validator", "validation_level", "validation_action") {
+		_err := setValidation(dbClient, collectionName, data)
+		if _err != nil {
+			return _err
+		}
+	}
+
+	if data.HasChanges("timeseries.0.granularity", "expire_after_seconds") {
+		_err := setTimeseries(dbClient, collectionName, data)
+		if _err != nil {
+			return _err
+		}
+	}
+
+	if data.HasChanges("size", "max") {
+		_err := resizeCappedCollection(dbClient, collectionName, data)
+		if _err != nil {
+			return _err
+		}
 	}
 
 	return resourceDatabaseCollectionRead(ctx, data, i)
@@ -166,6 +396,19 @@ func resourceDatabaseCollectionDelete(ctx context.Context, data *schema.Resource
 	return nil
 }
 
+// renameCollection moves a collection with its documents and indexes to a
+// new name, and to another database when db differs.
+func renameCollection(client *mongo.Client, db string, collectionName string, newDb string, newCollectionName string) diag.Diagnostics {
+	result := client.Database("admin").RunCommand(context.Background(), bson.D{
+		{Key: "renameCollection", Value: db + "." + collectionName},
+		{Key: "to", Value: newDb + "." + newCollectionName},
+	})
+	if result.Err() != nil {
+		return diag.Errorf("Could not rename the collection : %s ", result.Err())
+	}
+	return nil
+}
+
 func dropCollection(dbClient *mongo.Database, collectionName string, data *schema.ResourceData) diag.Diagnostics {
 	if data.Get("deletion_protection").(bool) {
 		return diag.Errorf("Can't delete collection because deletion protection is enabled")
@@ -213,6 +456,242 @@ func setChangeStreamPreAndPostImages(dbClient *mongo.Database, collectionName st
 	return nil
 }
 
+// setValidation applies the changed validation settings with collMod. An
+// empty validator document removes the validator.
+func setValidation(dbClient *mongo.Database, collectionName string, data *schema.ResourceData) diag.Diagnostics {
+	command := bson.D{{Key: "collMod", Value: collectionName}}
+	if data.HasChange("validator") {
+		validator, err := parseValidator(data.Get("validator").(string))
+		if err != nil {
+			return diag.Errorf("%s", err)
+		}
+		if validator == nil {
+			validator = bson.D{}
+		}
+		command = append(command, bson.E{Key: "validator", Value: validator})
+	}
+	if data.HasChange("validation_level") {
+		command = append(command, bson.E{Key: "validationLevel", Value: data.Get("validation_level").(string)})
+	}
+	if data.HasChange("validation_action") {
+		command = append(command, bson.E{Key: "validationAction", Value: data.Get("validation_action").(string)})
+	}
+
+	result := dbClient.RunCommand(context.Background(), command)
+	if result.Err() != nil {
+		return diag.Errorf("Failed to set validation: %s", result.Err())
+	}
+	return nil
+}
+
+// resizeCappedCollection changes the size and/or document limit of a capped
+// collection with collMod, which needs MongoDB 6.0 or later.
+func resizeCappedCollection(dbClient *mongo.Database, collectionName string, data *schema.ResourceData) diag.Diagnostics {
+	command := bson.D{{Key: "collMod", Value: collectionName}}
+	if data.HasChange("size") {
+		command = append(command, bson.E{Key: "cappedSize", Value: int64(data.Get("size").(int))})
+	}
+	if data.HasChange("max") {
+		command = append(command, bson.E{Key: "cappedMax", Value: int64(data.Get("max").(int))})
+	}
+
+	result := dbClient.RunCommand(context.Background(), command)
+	if result.Err() != nil {
+		return diag.Errorf("Failed to resize capped collection: %s", result.Err())
+	}
+	return nil
+}
+
+// suppressCappedSize ignores the rounding the server applies to the size of
+// capped collections: at least 4096 bytes and a multiple of 256.
+func suppressCappedSize(k, old, new string, d *schema.ResourceData) bool {
+	oldSize, oldErr := strconv.Atoi(old)
+	newSize, newErr := strconv.Atoi(new)
+	if oldErr != nil || newErr != nil || newSize == 0 {
+		return old == new
+	}
+	rounded := newSize
+	if rounded <= 4096 {
+		rounded = 4096
+	} else if rounded%256 != 0 {
+		rounded += 256 - rounded%256
+	}
+	return oldSize == newSize || oldSize == rounded
+}
+
+// setTimeseries applies a granularity increase and/or a new expiry with
+// collMod, everything else about a time series collection is immutable.
+func setTimeseries(dbClient *mongo.Database, collectionName string, data *schema.ResourceData) diag.Diagnostics {
+	command := bson.D{{Key: "collMod", Value: collectionName}}
+	if data.HasChange("timeseries.0.granularity") {
+		command = append(command, bson.E{Key: "timeseries", Value: bson.D{
+			{Key: "granularity", Value: data.Get("timeseries.0.granularity").(string)},
+		}})
+	}
+	if data.HasChange("expire_after_seconds") {
+		var expireAfterSeconds interface{} = "off"
+		if value := data.Get("expire_after_seconds").(int); value != 0 {
+			expireAfterSeconds = int64(value)
+		}
+		command = append(command, bson.E{Key: "expireAfterSeconds", Value: expireAfterSeconds})
+	}
+
+	result := dbClient.RunCommand(context.Background(), command)
+	if result.Err() != nil {
+		return diag.Errorf("Failed to update time series options: %s", result.Err())
+	}
+	return nil
+}
+
+var timeseriesGranularities = []string{"seconds", "minutes", "hours"}
+
+// timeseriesBucketSpans are the bucket_max_span_seconds and
+// bucket_rounding_seconds the server sets for each granularity.
+var timeseriesBucketSpans = map[string][2]int{
+	"seconds": {3600, 60},
+	"minutes": {86400, 3600},
+	"hours":   {2592000, 86400},
+}
+
+func resourceDatabaseCollectionCustomizeDiff(ctx context.Context, diff *schema.ResourceDiff, i interface{}) error {
+	// timeseries is computed, so a block left out of the configuration
+	// has to be cleared here
+	if config := diff.GetRawConfig(); !config.IsNull() && config.IsKnown() {
+		if blocks := config.GetAttr("timeseries"); blocks.IsKnown() && (blocks.IsNull() || blocks.LengthInt() == 0) {
+			if err := diff.SetNew("timeseries", []interface{}{}); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Time series collections can't be renamed, they are recreated instead
+	if diff.Id() != "" && diff.HasChanges("db", "name") && diff.Get("timeseries.#").(int) != 0 {
+		for _, key := range []string{"db", "name"} {
+			if diff.HasChange(key) {
+				if err := diff.ForceNew(key); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	// Turning a collection into a time series one or back needs a new collection
+	oldCount, newCount := diff.GetChange("timeseries.#")
+	if diff.Id() != "" && oldCount.(int) != newCount.(int) {
+		if err := diff.ForceNew("timeseries"); err != nil {
+			return err
+		}
+	}
+
+	if timeseries, ok := diff.GetOk("timeseries.0"); ok && newCount.(int) == 1 {
+		values := timeseries.(map[string]interface{})
+		maxSpan, rounding := values["bucket_max_span_seconds"].(int), values["bucket_rounding_seconds"].(int)
+		if maxSpan != rounding && diff.HasChanges("timeseries.0.bucket_max_span_seconds", "timeseries.0.bucket_rounding_seconds") {
+			return fmt.Errorf("timeseries bucket_max_span_seconds and bucket_rounding_seconds must be equal")
+		}
+	}
+
+	if diff.Id() != "" && oldCount.(int) == 1 && newCount.(int) == 1 {
+		// Bucket spans can only be changed through the granularity
+		for _, key := range []string{"timeseries.0.bucket_max_span_seconds", "timeseries.0.bucket_rounding_seconds"} {
+			if diff.HasChange(key) {
+				if err := diff.ForceNew(key); err != nil {
+					return err
+				}
+			}
+		}
+
+		// The server can only coarsen the granularity of existing buckets
+		if diff.HasChange("timeseries.0.granularity") {
+			oldGranularity, newGranularity := diff.GetChange("timeseries.0.granularity")
+			// An empty granularity means custom bucketing, which can't be converted
+			if oldGranularity.(string) == "" ||
+				indexOf(timeseriesGranularities, newGranularity.(string)) < indexOf(timeseriesGranularities, oldGranularity.(string)) {
+				if err := diff.ForceNew("timeseries.0.granularity"); err != nil {
+					return err
+				}
+			} else if spans, ok := timeseriesBucketSpans[newGranularity.(string)]; ok {
+				// collMod recomputes the bucket spans for the new granularity
+				values := diff.Get("timeseries.0").(map[string]interface{})
+				values["bucket_max_span_seconds"], values["bucket_rounding_seconds"] = spans[0], spans[1]
+				if err := diff.SetNew("timeseries", []interface{}{values}); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if diff.Get("expire_after_seconds").(int) != 0 && diff.Get("timeseries.#").(int) == 0 && diff.Get("clustered_index.#").(int) == 0 {
+		return fmt.Errorf("expire_after_seconds requires a timeseries or clustered_index collection")
+	}
+
+	if diff.Get("capped").(bool) {
+		if diff.NewValueKnown("size") && diff.Get("size").(int) == 0 {
+			return fmt.Errorf("size is required for capped collections")
+		}
+	} else if diff.Get("size").(int) != 0 || diff.Get("max").(int) != 0 {
+		return fmt.Errorf("size and max can only be set on capped collections")
+	}
+	return nil
+}
+
+func expandTimeseries(values map[string]interface{}) *options.TimeSeriesOptionsBuilder {
+	timeseries := options.TimeSeries().SetTimeField(values["time_field"].(string))
+	if metaField := values["meta_field"].(string); metaField != "" {
+		timeseries.SetMetaField(metaField)
+	}
+	if granularity := values["granularity"].(string); granularity != "" {
+		timeseries.SetGranularity(granularity)
+	}
+	if maxSpan := values["bucket_max_span_seconds"].(int); maxSpan != 0 {
+		timeseries.SetBucketMaxSpan(time.Duration(maxSpan) * time.Second)
+	}
+	if rounding := values["bucket_rounding_seconds"].(int); rounding != 0 {
+		timeseries.SetBucketRounding(time.Duration(rounding) * time.Second)
+	}
+	return timeseries
+}
+
+func flattenTimeseries(document bson.Raw) map[string]interface{} {
+	timeField, _ := document.Lookup("timeField").StringValueOK()
+	metaField, _ := document.Lookup("metaField").StringValueOK()
+	granularity, _ := document.Lookup("granularity").StringValueOK()
+	maxSpan, _ := document.Lookup("bucketMaxSpanSeconds").AsInt64OK()
+	rounding, _ := document.Lookup("bucketRoundingSeconds").AsInt64OK()
+	return map[string]interface{}{
+		"time_field":              timeField,
+		"meta_field":              metaField,
+		"granularity":             granularity,
+		"bucket_max_span_seconds": int(maxSpan),
+		"bucket_rounding_seconds": int(rounding),
+	}
+}
+
+func indexOf(values []string, value string) int {
+	for i, element := range values {
+		if element == value {
+			return i
+		}
+	}
+	return -1
+}
+
+func isEmptyDocument(document bson.Raw) bool {
+	elements, err := document.Elements()
+	return err == nil && len(elements) == 0
+}
+
+func parseValidator(validator string) (bson.D, error) {
+	if validator == "" {
+		return nil, nil
+	}
+	var document bson.D
+	if err := bson.UnmarshalExtJSON([]byte(validator), false, &document); err != nil {
+		return nil, fmt.Errorf("Invalid validator Extended JSON: %s", err)
+	}
+	return document, nil
+}
+
 func parseDbAndCollection(data *schema.ResourceData, i interface{}) (*mongo.Database, string, string, error) {
 	var config = i.(*MongoDatabaseConfiguration)
 	client, connectionError := MongoClientInit(config)
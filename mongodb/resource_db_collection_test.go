@@ -3,6 +3,7 @@ package mongodb
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
@@ -99,6 +100,349 @@ func TestAccMongoDBCollection_Update(t *testing.T) {
 	})
 }
 
+func TestAccMongoDBCollection_Validator(t *testing.T) {
+	var collectionName = acctest.RandomWithPrefix("tf-acc-test")
+	var databaseName = acctest.RandomWithPrefix("tf-acc-db")
+	resourceName := "mongodb_db_collection.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckMongoDBCollectionDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccMongoDBCollectionWithValidator(databaseName, collectionName, `["name"]`, "strict", "error"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMongoDBCollectionExists(resourceName),
+					resource.TestCheckResourceAttrSet(resourceName, "validator"),
+					resource.TestCheckResourceAttr(resourceName, "validation_level", "strict"),
+					resource.TestCheckResourceAttr(resourceName, "validation_action", "error"),
+				),
+			},
+			{
+				Config:   testAccMongoDBCollectionWithValidator(databaseName, collectionName, `["name"]`, "strict", "error"),
+				PlanOnly: true,
+			},
+			{
+				Config: testAccMongoDBCollectionWithValidator(databaseName, collectionName, `["name", "age"]`, "moderate", "warn"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMongoDBCollectionExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "validation_level", "moderate"),
+					resource.TestCheckResourceAttr(resourceName, "validation_action", "warn"),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"deletion_protection", "validator"},
+			},
+			{
+				Config: testAccMongoDBCollectionBasic(databaseName, collectionName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMongoDBCollectionExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "validator", ""),
+					resource.TestCheckResourceAttr(resourceName, "validation_level", "strict"),
+					resource.TestCheckResourceAttr(resourceName, "validation_action", "error"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccMongoDBCollection_Timeseries(t *testing.T) {
+	var collectionName = acctest.RandomWithPrefix("tf-acc-test")
+	var databaseName = acctest.RandomWithPrefix("tf-acc-db")
+	var uuid string
+	resourceName := "mongodb_db_collection.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckMongoDBCollectionDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccMongoDBCollectionTimeseries(databaseName, collectionName, "seconds", 3600),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMongoDBCollectionExists(resourceName),
+					testAccCheckMongoDBCollectionUUID(resourceName, &uuid),
+					resource.TestCheckResourceAttr(resourceName, "timeseries.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "timeseries.0.time_field", "timestamp"),
+					resource.TestCheckResourceAttr(resourceName, "timeseries.0.meta_field", "sensor"),
+					resource.TestCheckResourceAttr(resourceName, "timeseries.0.granularity", "seconds"),
+					resource.TestCheckResourceAttr(resourceName, "timeseries.0.bucket_max_span_seconds", "3600"),
+					resource.TestCheckResourceAttr(resourceName, "timeseries.0.bucket_rounding_seconds", "60"),
+					resource.TestCheckResourceAttr(resourceName, "expire_after_seconds", "3600"),
+				),
+			},
+			{
+				Config: testAccMongoDBCollectionTimeseries(databaseName, collectionName, "minutes", 7200),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMongoDBCollectionUUID(resourceName, &uuid),
+					resource.TestCheckResourceAttr(resourceName, "timeseries.0.granularity", "minutes"),
+					resource.TestCheckResourceAttr(resourceName, "timeseries.0.bucket_max_span_seconds", "86400"),
+					resource.TestCheckResourceAttr(resourceName, "timeseries.0.bucket_rounding_seconds", "3600"),
+					resource.TestCheckResourceAttr(resourceName, "expire_after_seconds", "7200"),
+				),
+			},
+			{
+				Config: testAccMongoDBCollectionTimeseries(databaseName, collectionName, "hours", 7200),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMongoDBCollectionUUID(resourceName, &uuid),
+					resource.TestCheckResourceAttr(resourceName, "timeseries.0.granularity", "hours"),
+					resource.TestCheckResourceAttr(resourceName, "timeseries.0.bucket_max_span_seconds", "2592000"),
+					resource.TestCheckResourceAttr(resourceName, "timeseries.0.bucket_rounding_seconds", "86400"),
+					resource.TestCheckResourceAttr(resourceName, "expire_after_seconds", "7200"),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"deletion_protection"},
+			},
+			{
+				Config: testAccMongoDBCollectionTimeseries(databaseName, collectionName, "seconds", 0),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMongoDBCollectionExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "timeseries.0.granularity", "seconds"),
+					resource.TestCheckResourceAttr(resourceName, "expire_after_seconds", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccMongoDBCollection_TimeseriesBuckets(t *testing.T) {
+	var collectionName = acctest.RandomWithPrefix("tf-acc-test")
+	var databaseName = acctest.RandomWithPrefix("tf-acc-db")
+	resourceName := "mongodb_db_collection.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckMongoDBCollectionDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "mongodb_db_collection" "test" {
+  db                  = "%s"
+  name                = "%s"
+  deletion_protection = false
+
+  timeseries {
+    time_field              = "timestamp"
+    bucket_max_span_seconds = 300
+    bucket_rounding_seconds = 300
+  }
+}
+`, databaseName, collectionName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMongoDBCollectionExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "timeseries.0.bucket_max_span_seconds", "300"),
+					resource.TestCheckResourceAttr(resourceName, "timeseries.0.bucket_rounding_seconds", "300"),
+				),
+			},
+			{
+				Config:      testAccMongoDBCollectionBasicWithExpiry(databaseName, collectionName),
+				ExpectError: regexp.MustCompile("expire_after_seconds requires"),
+			},
+		},
+	})
+}
+
+func TestAccMongoDBCollection_Capped(t *testing.T) {
+	var collectionName = acctest.RandomWithPrefix("tf-acc-test")
+	var databaseName = acctest.RandomWithPrefix("tf-acc-db")
+	var uuid string
+	resourceName := "mongodb_db_collection.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckMongoDBCollectionDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccMongoDBCollectionCapped(databaseName, collectionName, 1048576, 1000),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMongoDBCollectionExists(resourceName),
+					testAccCheckMongoDBCollectionUUID(resourceName, &uuid),
+					resource.TestCheckResourceAttr(resourceName, "capped", "true"),
+					resource.TestCheckResourceAttr(resourceName, "size", "1048576"),
+					resource.TestCheckResourceAttr(resourceName, "max", "1000"),
+				),
+			},
+			{
+				Config: testAccMongoDBCollectionCapped(databaseName, collectionName, 2097152, 5000),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMongoDBCollectionUUID(resourceName, &uuid),
+					resource.TestCheckResourceAttr(resourceName, "size", "2097152"),
+					resource.TestCheckResourceAttr(resourceName, "max", "5000"),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"deletion_protection"},
+			},
+			{
+				Config:      testAccMongoDBCollectionCapped(databaseName, collectionName, 0, 10),
+				ExpectError: regexp.MustCompile("size is required for capped collections"),
+			},
+		},
+	})
+}
+
+func TestAccMongoDBCollection_Clustered(t *testing.T) {
+	var collectionName = acctest.RandomWithPrefix("tf-acc-test")
+	var databaseName = acctest.RandomWithPrefix("tf-acc-db")
+	resourceName := "mongodb_db_collection.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckMongoDBCollectionDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccMongoDBCollectionClustered(databaseName, collectionName, 86400),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMongoDBCollectionExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "clustered_index.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "clustered_index.0.name", "by_id"),
+					resource.TestCheckResourceAttr(resourceName, "expire_after_seconds", "86400"),
+				),
+			},
+			{
+				Config: testAccMongoDBCollectionClustered(databaseName, collectionName, 3600),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "expire_after_seconds", "3600"),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"deletion_protection"},
+			},
+		},
+	})
+}
+
+func TestAccMongoDBCollection_Rename(t *testing.T) {
+	var collectionName = acctest.RandomWithPrefix("tf-acc-test")
+	var renamedCollectionName = acctest.RandomWithPrefix("tf-acc-renamed")
+	var databaseName = acctest.RandomWithPrefix("tf-acc-db")
+	var otherDatabaseName = acctest.RandomWithPrefix("tf-acc-db")
+	var uuid string
+	resourceName := "mongodb_db_collection.test"
+	indexResourceName := "mongodb_db_index.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckMongoDBCollectionDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccMongoDBCollectionRename(databaseName, collectionName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMongoDBCollectionExists(resourceName),
+					testAccCheckMongoDBCollectionUUID(resourceName, &uuid),
+					testAccCheckMongoDBIndexExists(indexResourceName),
+				),
+			},
+			{
+				PreConfig: func() {
+					client := testAccClient(t)
+					_, err := client.Database(databaseName).Collection(collectionName).InsertOne(context.Background(), bson.D{{Key: "email", Value: "a@example.com"}})
+					if err != nil {
+						t.Fatalf("error inserting document: %s", err)
+					}
+				},
+				Config: testAccMongoDBCollectionRename(databaseName, renamedCollectionName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMongoDBCollectionExists(resourceName),
+					testAccCheckMongoDBCollectionUUID(resourceName, &uuid),
+					testAccCheckMongoDBCollectionCount(databaseName, renamedCollectionName, 1),
+					resource.TestCheckResourceAttr(resourceName, "name", renamedCollectionName),
+					testAccCheckMongoDBIndexExists(indexResourceName),
+					resource.TestCheckResourceAttr(indexResourceName, "collection", renamedCollectionName),
+				),
+			},
+			{
+				Config: testAccMongoDBCollectionRename(otherDatabaseName, renamedCollectionName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMongoDBCollectionExists(resourceName),
+					testAccCheckMongoDBCollectionCount(otherDatabaseName, renamedCollectionName, 1),
+					resource.TestCheckResourceAttr(resourceName, "db", otherDatabaseName),
+					testAccCheckMongoDBIndexExists(indexResourceName),
+					resource.TestCheckResourceAttr(indexResourceName, "db", otherDatabaseName),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckMongoDBCollectionCount(db, collectionName string, expected int64) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		config := testAccProvider.Meta().(*MongoDatabaseConfiguration)
+		client, err := MongoClientInit(config)
+		if err != nil {
+			return fmt.Errorf("error connecting to database: %s", err)
+		}
+
+		count, err := client.Database(db).Collection(collectionName).CountDocuments(context.Background(), bson.D{})
+		if err != nil {
+			return fmt.Errorf("error counting documents: %s", err)
+		}
+		if count != expected {
+			return fmt.Errorf("collection %s.%s has %d documents, expected %d", db, collectionName, count, expected)
+		}
+		return nil
+	}
+}
+
+// testAccCheckMongoDBCollectionUUID records the collection UUID on first use
+// and afterwards fails if the collection was replaced.
+func testAccCheckMongoDBCollectionUUID(resourceName string, uuid *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("not found: %s", resourceName)
+		}
+
+		config := testAccProvider.Meta().(*MongoDatabaseConfiguration)
+		client, err := MongoClientInit(config)
+		if err != nil {
+			return fmt.Errorf("error connecting to database: %s", err)
+		}
+
+		db, collectionName, err := resourceDatabaseCollectionParseId(rs.Primary.ID)
+		if err != nil {
+			return fmt.Errorf("error parsing ID: %s", err)
+		}
+
+		specifications, err := client.Database(db).ListCollectionSpecifications(context.Background(), bson.M{"name": collectionName})
+		if err != nil {
+			return fmt.Errorf("error listing collections: %s", err)
+		}
+		if len(specifications) != 1 {
+			return fmt.Errorf("collection %s does not exist in database %s", collectionName, db)
+		}
+
+		if specifications[0].UUID == nil {
+			return fmt.Errorf("collection %s has no UUID", collectionName)
+		}
+		current := fmt.Sprintf("%x", specifications[0].UUID.Data)
+		if *uuid == "" {
+			*uuid = current
+		} else if *uuid != current {
+			return fmt.Errorf("collection %s was replaced instead of updated in place", collectionName)
+		}
+		return nil
+	}
+}
+
 func testAccCheckMongoDBCollectionExists(resourceName string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[resourceName]
@@ -187,4 +531,102 @@ resource "mongodb_db_collection" "test" {
   change_stream_pre_and_post_images   = true
 }
 `, dbName, collectionName)
-}
\ No newline at end of file
+}
+func testAccMongoDBCollectionWithValidator(dbName, collectionName, required, level, action string) string {
+	return fmt.Sprintf(`
+resource "mongodb_db_collection" "test" {
+  db                  = "%s"
+  name                = "%s"
+  deletion_protection = false
+  validation_level    = "%s"
+  validation_action   = "%s"
+
+  validator = jsonencode({
+    "$jsonSchema" = {
+      bsonType = "object"
+      required = %s
+      properties = {
+        name = { bsonType = "string" }
+        age  = { bsonType = "int", minimum = 0 }
+      }
+    }
+  })
+}
+`, dbName, collectionName, level, action, required)
+}
+
+func testAccMongoDBCollectionTimeseries(dbName, collectionName, granularity string, expireAfterSeconds int) string {
+	return fmt.Sprintf(`
+resource "mongodb_db_collection" "test" {
+  db                   = "%s"
+  name                 = "%s"
+  deletion_protection  = false
+  expire_after_seconds = %d
+
+  timeseries {
+    time_field  = "timestamp"
+    meta_field  = "sensor"
+    granularity = "%s"
+  }
+}
+`, dbName, collectionName, expireAfterSeconds, granularity)
+}
+
+func testAccMongoDBCollectionBasicWithExpiry(dbName, collectionName string) string {
+	return fmt.Sprintf(`
+resource "mongodb_db_collection" "test" {
+  db                   = "%s"
+  name                 = "%s"
+  deletion_protection  = false
+  expire_after_seconds = 60
+}
+`, dbName, collectionName)
+}
+
+func testAccMongoDBCollectionCapped(dbName, collectionName string, size, max int) string {
+	return fmt.Sprintf(`
+resource "mongodb_db_collection" "test" {
+  db                  = "%s"
+  name                = "%s"
+  deletion_protection = false
+  capped              = true
+  size                = %d
+  max                 = %d
+}
+`, dbName, collectionName, size, max)
+}
+
+func testAccMongoDBCollectionClustered(dbName, collectionName string, expireAfterSeconds int) string {
+	return fmt.Sprintf(`
+resource "mongodb_db_collection" "test" {
+  db                   = "%s"
+  name                 = "%s"
+  deletion_protection  = false
+  expire_after_seconds = %d
+
+  clustered_index {
+    name = "by_id"
+  }
+}
+`, dbName, collectionName, expireAfterSeconds)
+}
+
+func testAccMongoDBCollectionRename(dbName, collectionName string) string {
+	return fmt.Sprintf(`
+resource "mongodb_db_collection" "test" {
+  db                  = "%s"
+  name                = "%s"
+  deletion_protection = false
+}
+
+resource "mongodb_db_index" "test" {
+  db         = mongodb_db_collection.test.db
+  collection = mongodb_db_collection.test.name
+  name       = "by_email"
+  keys {
+    field = "email"
+    value = "1"
+  }
+}
+`, dbName, collectionName)
+}
@@ -0,0 +1,179 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+func resourceDatabase() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceDatabaseCreate,
+		ReadContext:   resourceDatabaseRead,
+		UpdateContext: resourceDatabaseUpdate,
+		DeleteContext: resourceDatabaseDelete,
+		CustomizeDiff: resourceDatabaseCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				ValidateDiagFunc: validateDiagFunc(validation.All(
+					validation.StringLenBetween(1, 63),
+					validation.StringDoesNotContainAny("/\\. \"$*<>:|?"),
+				)),
+			},
+			"marker_collection": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "_terraform",
+				Description: "Collection created to materialise the database when it does not exist yet",
+				ValidateDiagFunc: validateDiagFunc(validation.All(
+					validation.StringIsNotEmpty,
+					validation.StringDoesNotContainAny("$"),
+					validation.StringDoesNotMatch(regexp.MustCompile(`^system\.`), "must not be a system collection"),
+				)),
+			},
+			"deletion_protection": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"drop_on_destroy": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Run dropDatabase on destroy instead of only removing the database from the state",
+			},
+		},
+	}
+}
+
+func resourceDatabaseCreate(ctx context.Context, data *schema.ResourceData, i interface{}) diag.Diagnostics {
+	var config = i.(*MongoDatabaseConfiguration)
+	client, connectionError := MongoClientInit(config)
+	if connectionError != nil {
+		return diag.Errorf("Error connecting to database : %s ", connectionError)
+	}
+	var name = data.Get("name").(string)
+	var markerCollection = data.Get("marker_collection").(string)
+
+	exists, err := databaseExists(client, name)
+	if err != nil {
+		return diag.Errorf("Failed to list databases : %s ", err)
+	}
+	// MongoDB only materialises a database with its first collection
+	if !exists {
+		err = client.Database(name).CreateCollection(context.Background(), markerCollection)
+		var serverError mongo.ServerError
+		if err != nil && !(errors.As(err, &serverError) && serverError.HasErrorCode(namespaceExistsCode)) {
+			return diag.Errorf("Could not create the database : %s ", err)
+		}
+	}
+
+	SetId(data, []string{name})
+	return resourceDatabaseRead(ctx, data, i)
+}
+
+func resourceDatabaseRead(ctx context.Context, data *schema.ResourceData, i interface{}) diag.Diagnostics {
+	var config = i.(*MongoDatabaseConfiguration)
+	client, connectionError := MongoClientInit(config)
+	if connectionError != nil {
+		return diag.Errorf("Error connecting to database : %s ", connectionError)
+	}
+	name, err := resourceDatabaseParseId(data.State().ID)
+	if err != nil {
+		return diag.Errorf("%s", err)
+	}
+
+	exists, err := databaseExists(client, name)
+	if err != nil {
+		return diag.Errorf("Failed to list databases : %s ", err)
+	}
+	if !exists {
+		tflog.Warn(ctx, fmt.Sprintf("Database %s no longer exists, removing it from the state", name))
+		data.SetId("")
+		return nil
+	}
+
+	_ = data.Set("name", name)
+	if _, ok := data.GetOk("marker_collection"); !ok {
+		_ = data.Set("marker_collection", "_terraform")
+	}
+	_ = data.Set("deletion_protection", data.Get("deletion_protection").(bool))
+	_ = data.Set("drop_on_destroy", data.Get("drop_on_destroy").(bool))
+	return nil
+}
+
+func resourceDatabaseUpdate(ctx context.Context, data *schema.ResourceData, i interface{}) diag.Diagnostics {
+	// deletion_protection and drop_on_destroy only live in the state
+	return resourceDatabaseRead(ctx, data, i)
+}
+
+func resourceDatabaseDelete(ctx context.Context, data *schema.ResourceData, i interface{}) diag.Diagnostics {
+	var config = i.(*MongoDatabaseConfiguration)
+	client, connectionError := MongoClientInit(config)
+	if connectionError != nil {
+		return diag.Errorf("Error connecting to database : %s ", connectionError)
+	}
+	name, err := resourceDatabaseParseId(data.State().ID)
+	if err != nil {
+		return diag.Errorf("%s", err)
+	}
+
+	if !data.Get("drop_on_destroy").(bool) {
+		tflog.Info(ctx, fmt.Sprintf("Leaving database %s in place, drop_on_destroy is disabled", name))
+		return nil
+	}
+	if data.Get("deletion_protection").(bool) {
+		return diag.Errorf("Can't drop database because deletion protection is enabled")
+	}
+
+	err = client.Database(name).Drop(context.Background())
+	if err != nil {
+		return diag.Errorf("Could not drop the database : %s ", err)
+	}
+	return nil
+}
+
+func resourceDatabaseCustomizeDiff(ctx context.Context, diff *schema.ResourceDiff, i interface{}) error {
+	switch name := diff.Get("name").(string); name {
+	case "admin", "local", "config":
+		if diff.Get("drop_on_destroy").(bool) {
+			return fmt.Errorf("drop_on_destroy can not be enabled on the %s system database", name)
+		}
+	}
+	return nil
+}
+
+func databaseExists(client *mongo.Client, name string) (bool, error) {
+	names, err := client.ListDatabaseNames(context.Background(), bson.D{{Key: "name", Value: name}})
+	if err != nil {
+		return false, err
+	}
+	return len(names) != 0, nil
+}
+
+func resourceDatabaseParseId(id string) (string, error) {
+	parts, err := ParseId(id, 1)
+	if err != nil {
+		return "", err
+	}
+	if strings.Contains(parts[0], ".") {
+		return "", fmt.Errorf("unexpected format of ID (%s), expected database", id)
+	}
+	return parts[0], nil
+}
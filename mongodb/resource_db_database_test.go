@@ -0,0 +1,158 @@
+package mongodb
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccMongoDBDatabase_Basic(t *testing.T) {
+	var databaseName = acctest.RandomWithPrefix("tf-acc-db")
+	resourceName := "mongodb_database.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckMongoDBDatabaseDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccMongoDBDatabase(databaseName, false, true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMongoDBDatabaseExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "name", databaseName),
+					resource.TestCheckResourceAttr(resourceName, "marker_collection", "_terraform"),
+					resource.TestCheckResourceAttr(resourceName, "deletion_protection", "false"),
+					resource.TestCheckResourceAttr(resourceName, "drop_on_destroy", "true"),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"deletion_protection", "drop_on_destroy"},
+			},
+		},
+	})
+}
+
+func TestAccMongoDBDatabase_DeletionProtection(t *testing.T) {
+	var databaseName = acctest.RandomWithPrefix("tf-acc-db")
+	resourceName := "mongodb_database.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckMongoDBDatabaseDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccMongoDBDatabase(databaseName, true, true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMongoDBDatabaseExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "deletion_protection", "true"),
+				),
+			},
+			{
+				Config:      testAccMongoDBDatabase(databaseName, true, true),
+				Destroy:     true,
+				ExpectError: regexp.MustCompile("deletion protection is enabled"),
+			},
+			{
+				Config: testAccMongoDBDatabase(databaseName, false, true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMongoDBDatabaseExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "deletion_protection", "false"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccMongoDBDatabase_SystemDatabase(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccMongoDBDatabase("admin", false, true),
+				ExpectError: regexp.MustCompile("system database"),
+			},
+		},
+	})
+}
+
+func testAccCheckMongoDBDatabaseExists(resourceName string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("not found: %s", resourceName)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("no ID is set")
+		}
+
+		config := testAccProvider.Meta().(*MongoDatabaseConfiguration)
+		client, err := MongoClientInit(config)
+		if err != nil {
+			return fmt.Errorf("error connecting to database: %s", err)
+		}
+
+		name, err := resourceDatabaseParseId(rs.Primary.ID)
+		if err != nil {
+			return fmt.Errorf("error parsing ID: %s", err)
+		}
+
+		exists, err := databaseExists(client, name)
+		if err != nil {
+			return fmt.Errorf("error listing databases: %s", err)
+		}
+		if !exists {
+			return fmt.Errorf("database %s does not exist", name)
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckMongoDBDatabaseDestroy(s *terraform.State) error {
+	config := testAccProvider.Meta().(*MongoDatabaseConfiguration)
+	client, err := MongoClientInit(config)
+	if err != nil {
+		return fmt.Errorf("error connecting to database: %s", err)
+	}
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "mongodb_database" {
+			continue
+		}
+
+		name, err := resourceDatabaseParseId(rs.Primary.ID)
+		if err != nil {
+			continue // If we can't parse the ID, assume it's destroyed
+		}
+
+		exists, err := databaseExists(client, name)
+		if err != nil {
+			continue // If we can't list databases, assume it's destroyed
+		}
+		if exists {
+			return fmt.Errorf("database %s still exists", name)
+		}
+	}
+
+	return nil
+}
+
+func testAccMongoDBDatabase(name string, deletionProtection, dropOnDestroy bool) string {
+	return fmt.Sprintf(`
+resource "mongodb_database" "test" {
+  name                = "%s"
+  deletion_protection = %t
+  drop_on_destroy     = %t
+}
+`, name, deletionProtection, dropOnDestroy)
+}
@@ -2,7 +2,10 @@ package mongodb
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -10,6 +13,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
@@ -21,19 +25,32 @@ func resourceDatabaseIndex() *schema.Resource {
 		ReadContext:   resourceDatabaseIndexRead,
 		UpdateContext: resourceDatabaseIndexUpdate,
 		DeleteContext: resourceDatabaseIndexDelete,
+		CustomizeDiff: resourceDatabaseIndexCustomizeDiff,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+		},
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Type:    resourceDatabaseIndexV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceDatabaseIndexStateUpgradeV0,
+				Version: 0,
+			},
+		},
 		Schema: map[string]*schema.Schema{
 			"db": {
-				Type:     schema.TypeString,
-				ForceNew: true,
-				Required: true,
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Changing it follows the collection to its new database",
 			},
 			"collection": {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Changing it follows the collection to its new name",
 			},
 			"keys": {
 				Type:     schema.TypeList,
@@ -47,9 +64,14 @@ func resourceDatabaseIndex() *schema.Resource {
 							Required: true,
 						},
 						"value": {
-							Type:     schema.TypeString,
-							ForceNew: true,
-							Required: true,
+							Type:             schema.TypeString,
+							ForceNew:         true,
+							Required:         true,
+							Description:      "1, -1, text, 2d, 2dsphere or hashed",
+							ValidateDiagFunc: validateDiagFunc(validateIndexKeyValue),
+							DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+								return old == legacyIndexKeyValue(new)
+							},
 						},
 					},
 				},
@@ -63,6 +85,10 @@ func resourceDatabaseIndex() *schema.Resource {
 					if len(old) > 0 && len(new) == 0 {
 						return true
 					}
+					// an adopted index keeps the name it was found under
+					if len(old) > 0 && new == d.Get("requested_name").(string) {
+						return true
+					}
 					return false
 				},
 			},
@@ -73,41 +99,124 @@ func resourceDatabaseIndex() *schema.Resource {
 				Default:     "",
 				Description: "A JSON string representing the partialFilterExpression for a partial index. Example: {\"field\": {\"$exists\": true}}",
 			},
+			"collation": collationSchema(true),
 			"hidden": {
 				Type:        schema.TypeBool,
 				Optional:    true,
 				Default:     false,
 				Description: "If true, the index is hidden from the query planner (MongoDB 4.4+). Can be toggled without recreating the index.",
 			},
-			//"unique": {
-			//	Type:     schema.TypeBool,
-			//	Optional: true,
-			//	Default:  false,
-			//},
-			//"sparse": {
-			//	Type:     schema.TypeBool,
-			//	Optional: true,
-			//	Default:  false,
-			//},
-			//"bits": {
-			//	Type:     schema.TypeInt,
-			//	Optional: true,
-			//	Default:  26,
-			//},
-			//"max": {
-			//	Type:     schema.TypeFloat,
-			//	Optional: true,
-			//	Default:  180.0,
-			//},
-			//"min": {
-			//	Type:     schema.TypeFloat,
-			//	Optional: true,
-			//	Default:  -180.0,
-			//},
+			"unique": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Reject documents with the same key. Turning it on converts the index in place",
+			},
+			"sparse": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				ForceNew:    true,
+				Description: "Only index documents containing the indexed fields",
+			},
+			"expire_after_seconds": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          -1,
+				Description:      "Make it a TTL index removing documents this many seconds after the date in the indexed field, -1 for none. Changed in place unless it is removed",
+				ValidateDiagFunc: validateDiagFunc(validation.IntAtLeast(-1)),
+			},
+			"bits": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "Precision of the geohash of a 2d index",
+			},
+			"min": {
+				Type:        schema.TypeFloat,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "Lower bound of the coordinates of a 2d index",
+			},
+			"max": {
+				Type:        schema.TypeFloat,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "Upper bound of the coordinates of a 2d index",
+			},
+			"weights": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "Weight of each field of a text index",
+				Elem: &schema.Schema{
+					Type: schema.TypeInt,
+				},
+			},
+			"default_language": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "Language of a text index",
+			},
+			"language_override": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "Field of the documents overriding the language of a text index",
+			},
+			"wildcard_projection": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				Description:      "Extended JSON document of the fields a wildcard index includes or excludes",
+				ValidateDiagFunc: validateDiagFunc(validateExtJSONDocument),
+				DiffSuppressFunc: suppressEquivalentExtJSON,
+			},
+			"index_2dsphere_version": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "Version of a 2dsphere index, the 2dsphereIndexVersion option",
+			},
+			"adopt_existing": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Take over an index with the same key pattern that already exists under another name instead of failing",
+			},
+			"adopt_rebuild": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Let adopt_existing drop and rebuild an existing index whose options can't be changed in place",
+			},
+			"requested_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Configured name of an index adopted under its existing name",
+			},
+			"commit_quorum": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Voting replica set members that must be ready to commit the index build: votingMembers, majority, a number or a replica set tag. Only used when the index is built",
+				// There is nothing to update on a built index
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return d.Id() != ""
+				},
+			},
 			"timeout": {
-				Type:     schema.TypeInt,
-				Optional: true,
-				Default:  30,
+				Type:       schema.TypeInt,
+				Optional:   true,
+				Default:    30,
+				Deprecated: "Has no effect, index builds wait as long as the create timeout of a timeouts block allows",
 			},
 		},
 	}
@@ -122,7 +231,7 @@ func resourceDatabaseIndexCreate(ctx context.Context, data *schema.ResourceData,
 	var db = data.Get("db").(string)
 	var collectionName = data.Get("collection").(string)
 
-	indexName, diags := createIndex(client, db, collectionName, data)
+	indexName, diags := createIndex(ctx, client, db, collectionName, data, data.Timeout(schema.TimeoutCreate))
 	if diags != nil {
 		return diags
 	}
@@ -170,30 +279,44 @@ func resourceDatabaseIndexRead(ctx context.Context, data *schema.ResourceData, i
 			if k == "name" && v == indexName {
 				// In MongoDB driver v2, index keys are returned as bson.D (ordered document)
 				keysPrimitives := result["key"].(bson.D)
-				for _, elem := range keysPrimitives {
-					keyMap := map[string]interface{}{
-						"field": elem.Key,
-						"value": fmt.Sprintf("%v", elem.Value),
-					}
-					indexKeys = append(indexKeys, keyMap)
+				weightsDoc, _ := result["weights"].(bson.D)
+				indexKeys = flattenIndexKeys(keysPrimitives, weightsDoc, data.Get("keys").([]interface{}))
+
+				_ = data.Set("unique", result["unique"] == true)
+				_ = data.Set("sparse", result["sparse"] == true)
+				if expireAfterSeconds, ok := indexNumber(result["expireAfterSeconds"]); ok {
+					_ = data.Set("expire_after_seconds", int(expireAfterSeconds))
+				} else {
+					_ = data.Set("expire_after_seconds", -1)
 				}
-				
-				// Check for unique option
-				if unique, ok := result["unique"]; ok {
-					uniqueMap := map[string]interface{}{
-						"field": "unique",
-						"value": fmt.Sprintf("%v", unique),
-					}
-					indexKeys = append(indexKeys, uniqueMap)
+				if bits, ok := indexNumber(result["bits"]); ok {
+					_ = data.Set("bits", int(bits))
+				}
+				if minimum, ok := indexNumber(result["min"]); ok {
+					_ = data.Set("min", minimum)
 				}
-				
-				// Check for TTL index (expireAfterSeconds option)
-				if expireAfter, ok := result["expireAfterSeconds"]; ok {
-					ttlMap := map[string]interface{}{
-						"field": "expireAfterSeconds",
-						"value": fmt.Sprintf("%v", expireAfter),
+				if maximum, ok := indexNumber(result["max"]); ok {
+					_ = data.Set("max", maximum)
+				}
+				if weightsDoc != nil {
+					_ = data.Set("weights", flattenIndexWeights(weightsDoc, data.Get("weights").(map[string]interface{})))
+				}
+				if defaultLanguage, ok := result["default_language"].(string); ok {
+					_ = data.Set("default_language", defaultLanguage)
+				}
+				if languageOverride, ok := result["language_override"].(string); ok {
+					_ = data.Set("language_override", languageOverride)
+				}
+				if projection, ok := result["wildcardProjection"]; ok {
+					projectionBytes, err := bson.MarshalExtJSON(projection, false, false)
+					if err == nil {
+						_ = data.Set("wildcard_projection", string(projectionBytes))
 					}
-					indexKeys = append(indexKeys, ttlMap)
+				} else {
+					_ = data.Set("wildcard_projection", "")
+				}
+				if version, ok := indexNumber(result["2dsphereIndexVersion"]); ok {
+					_ = data.Set("index_2dsphere_version", int(version))
 				}
 
 				// Check for partialFilterExpression
@@ -204,6 +327,12 @@ func resourceDatabaseIndexRead(ctx context.Context, data *schema.ResourceData, i
 					}
 				}
 
+				var collation bson.Raw
+				if document, ok := result["collation"]; ok {
+					collation, _ = bson.Marshal(document)
+				}
+				_ = data.Set("collation", flattenCollation(collation, data.Get("collation").([]interface{})))
+
 				// Check for hidden
 				if hidden, ok := result["hidden"]; ok {
 					if hiddenBool, isBool := hidden.(bool); isBool {
@@ -236,7 +365,14 @@ func resourceDatabaseIndexRead(ctx context.Context, data *schema.ResourceData, i
 }
 
 func resourceDatabaseIndexUpdate(ctx context.Context, data *schema.ResourceData, i interface{}) diag.Diagnostics {
-	if data.HasChange("hidden") {
+	if data.HasChanges("db", "collection") {
+		diags := moveIndex(ctx, data, i)
+		if diags != nil {
+			return diags
+		}
+	}
+
+	if data.HasChanges("hidden", "expire_after_seconds", "unique") {
 		var config = i.(*MongoDatabaseConfiguration)
 		client, connectionError := MongoClientInit(config)
 		if connectionError != nil {
@@ -247,20 +383,33 @@ func resourceDatabaseIndexUpdate(ctx context.Context, data *schema.ResourceData,
 		if err != nil {
 			return diag.Errorf("%s", err)
 		}
-
-		hidden := data.Get("hidden").(bool)
 		dbClient := client.Database(db)
 
 		// Use collMod command to toggle hidden flag (MongoDB 4.4+)
-		result := dbClient.RunCommand(context.Background(), bson.D{
-			{Key: "collMod", Value: collectionName},
-			{Key: "index", Value: bson.D{
-				{Key: "name", Value: indexName},
-				{Key: "hidden", Value: hidden},
-			}},
-		})
-		if result.Err() != nil {
-			return diag.Errorf("Failed to update index hidden state: %s", result.Err())
+		if data.HasChange("hidden") {
+			err = modifyIndex(dbClient, collectionName, indexName, bson.E{Key: "hidden", Value: data.Get("hidden").(bool)})
+			if err != nil {
+				return diag.Errorf("Failed to update index hidden state: %s", err)
+			}
+		}
+
+		// Removing the TTL forces a new index, see resourceDatabaseIndexCustomizeDiff
+		if data.HasChange("expire_after_seconds") {
+			expireAfterSeconds := int64(data.Get("expire_after_seconds").(int))
+			err = modifyIndex(dbClient, collectionName, indexName, bson.E{Key: "expireAfterSeconds", Value: expireAfterSeconds})
+			if err != nil {
+				return diag.Errorf("Failed to update index expireAfterSeconds: %s", err)
+			}
+		}
+
+		// Turning unique off forces a new index, see resourceDatabaseIndexCustomizeDiff
+		if data.HasChange("unique") {
+			diags := convertIndexToUnique(ctx, dbClient, collectionName, indexName, data)
+			if diags != nil {
+				// keep unique off in the state, so the next apply tries again
+				data.Partial(true)
+				return diags
+			}
 		}
 	}
 
@@ -288,7 +437,287 @@ func resourceDatabaseIndexDelete(ctx context.Context, data *schema.ResourceData,
 	return nil
 }
 
-func createIndex(client *mongo.Client, db string, collectionName string, data *schema.ResourceData) (string, diag.Diagnostics) {
+func resourceDatabaseIndexCustomizeDiff(ctx context.Context, diff *schema.ResourceDiff, i interface{}) error {
+	wildcard := false
+	for _, _key := range diff.Get("keys").([]interface{}) {
+		key, ok := _key.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		field, _ := key["field"].(string)
+		value, _ := key["value"].(string)
+		wildcard = wildcard || field == "$**"
+		if attribute := magicIndexKeyAttribute(field, value); attribute != "" {
+			return fmt.Errorf("keys entry %q is no longer taken as an index option, set the %s attribute instead", field, attribute)
+		}
+		value = legacyIndexKeyValue(value)
+		if value != "" && indexOf(indexKeyValues, value) < 0 {
+			return fmt.Errorf("keys value %q of %q must be one of %s", value, field, strings.Join(indexKeyValues, ", "))
+		}
+		if isWildcardIndexKey(field) && value != "1" && value != "-1" && !(field == "$**" && value == "text") {
+			return fmt.Errorf("wildcard key %q must have a value of 1 or -1", field)
+		}
+	}
+	if diff.Get("wildcard_projection").(string) != "" && !wildcard {
+		return fmt.Errorf("wildcard_projection needs a \"$**\" key")
+	}
+
+	if diff.Id() != "" && diff.HasChange("unique") && !diff.Get("unique").(bool) {
+		if err := diff.ForceNew("unique"); err != nil {
+			return err
+		}
+	}
+
+	// collMod changes the TTL of an index and turns a single field index
+	// into a TTL index, it can't make a TTL index a regular one again
+	if diff.Id() != "" && diff.HasChange("expire_after_seconds") {
+		oldExpire, newExpire := diff.GetChange("expire_after_seconds")
+		if newExpire.(int) < 0 || (oldExpire.(int) < 0 && len(diff.Get("keys").([]interface{})) != 1) {
+			return diff.ForceNew("expire_after_seconds")
+		}
+	}
+	return nil
+}
+
+// indexKeyValues are the index types a keys value can have.
+var indexKeyValues = []string{"1", "-1", "text", "2d", "2dsphere", "hashed"}
+
+func isWildcardIndexKey(field string) bool {
+	return field == "$**" || strings.HasSuffix(field, ".$**")
+}
+
+func expandIndexKeyValue(value string) interface{} {
+	switch legacyIndexKeyValue(value) {
+	case "1":
+		return 1
+	case "-1":
+		return -1
+	}
+	return value
+}
+
+// flattenIndexKeys turns the key pattern the server reports back into the
+// declared one. A text index is stored with _fts and _ftsx keys in place of
+// its fields, which are only listed by the weights, in field name order, so
+// the declared order of current is kept when it has the same text fields.
+func flattenIndexKeys(key bson.D, weights bson.D, current []interface{}) []interface{} {
+	var textFields []string
+	for _, elem := range weights {
+		textFields = append(textFields, elem.Key)
+	}
+	var currentTextFields []string
+	for _, _key := range current {
+		if key, ok := _key.(map[string]interface{}); ok && key["value"] == "text" {
+			currentTextFields = append(currentTextFields, key["field"].(string))
+		}
+	}
+	if sameStrings(textFields, currentTextFields) {
+		textFields = currentTextFields
+	}
+
+	indexKeys := make([]interface{}, 0, len(key))
+	for _, elem := range key {
+		switch elem.Key {
+		case "_fts":
+			for _, field := range textFields {
+				indexKeys = append(indexKeys, map[string]interface{}{"field": field, "value": "text"})
+			}
+		case "_ftsx":
+		default:
+			indexKeys = append(indexKeys, map[string]interface{}{"field": elem.Key, "value": flattenIndexKeyValue(elem.Value)})
+		}
+	}
+	return indexKeys
+}
+
+func flattenIndexKeyValue(value interface{}) string {
+	if number, ok := indexNumber(value); ok {
+		return strconv.FormatFloat(number, 'f', -1, 64)
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// flattenIndexWeights leaves out the default weight of 1 the server reports
+// for every text field, unless the weight is already in current.
+func flattenIndexWeights(weights bson.D, current map[string]interface{}) map[string]interface{} {
+	flattened := map[string]interface{}{}
+	for _, elem := range weights {
+		weight, _ := indexNumber(elem.Value)
+		if _, ok := current[elem.Key]; ok || weight != 1 {
+			flattened[elem.Key] = int(weight)
+		}
+	}
+	return flattened
+}
+
+// sameStrings tells whether both hold the same strings, in any order.
+func sameStrings(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	counts := map[string]int{}
+	for _, value := range a {
+		counts[value]++
+	}
+	for _, value := range b {
+		if counts[value] == 0 {
+			return false
+		}
+		counts[value]--
+	}
+	return true
+}
+
+// legacyIndexKeyValue maps the "true" and "false" keys values earlier versions
+// accepted to "1", the ascending key the server took them for.
+func legacyIndexKeyValue(value string) string {
+	if value == "true" || value == "false" {
+		return "1"
+	}
+	return value
+}
+
+func validateIndexKeyValue(i interface{}, k string) ([]string, []error) {
+	value, _ := i.(string)
+	if legacyIndexKeyValue(value) != value {
+		return []string{fmt.Sprintf("%s %q is deprecated, use \"1\" instead", k, value)}, nil
+	}
+	return nil, nil
+}
+
+// magicIndexKeyAttribute returns the attribute replacing a keys entry that
+// older versions took as an index option, or "" for a regular key.
+func magicIndexKeyAttribute(field string, value string) string {
+	if strings.ToLower(field) == "unique" && (strings.ToLower(value) == "true" || strings.ToLower(value) == "false") {
+		return "unique"
+	}
+	if field == "expireAfterSeconds" {
+		if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+			return "expire_after_seconds"
+		}
+	}
+	return ""
+}
+
+// modifyIndex changes an option of an existing index with collMod.
+func modifyIndex(dbClient *mongo.Database, collectionName string, indexName string, option bson.E) error {
+	result := dbClient.RunCommand(context.Background(), bson.D{
+		{Key: "collMod", Value: collectionName},
+		{Key: "index", Value: bson.D{
+			{Key: "name", Value: indexName},
+			option,
+		}},
+	})
+	return result.Err()
+}
+
+// convertIndexToUnique makes an index unique in two steps (MongoDB 6.0+).
+// prepareUnique first makes the index reject new duplicates, then unique
+// converts it, which fails while the collection still holds duplicates.
+func convertIndexToUnique(ctx context.Context, dbClient *mongo.Database, collectionName string, indexName string, data *schema.ResourceData) diag.Diagnostics {
+	err := modifyIndex(dbClient, collectionName, indexName, bson.E{Key: "prepareUnique", Value: true})
+	if err != nil {
+		return diag.Errorf("Failed to prepare the index for unique: %s", err)
+	}
+	err = modifyIndex(dbClient, collectionName, indexName, bson.E{Key: "unique", Value: true})
+	if err == nil {
+		return nil
+	}
+
+	duplicates, findErr := findDuplicateKeys(ctx, dbClient.Collection(collectionName), data)
+	if findErr != nil || len(duplicates) == 0 {
+		tflog.Debug(ctx, fmt.Sprintf("Could not list duplicate keys: %v", findErr))
+		return diag.Errorf("Failed to convert the index to unique: %s", err)
+	}
+	return diag.Diagnostics{
+		{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("Index %s can't be made unique, %s.%s has duplicate keys", indexName, dbClient.Name(), collectionName),
+			Detail: fmt.Sprintf("Documents sharing these keys need to be removed or changed first:\n\n  %s\n\n"+
+				"The index already rejects new duplicates (prepareUnique), the next apply converts it once the duplicates are resolved.",
+				strings.Join(duplicates, "\n  ")),
+		},
+	}
+}
+
+// maxDuplicateKeys limits how many duplicate keys are reported.
+const maxDuplicateKeys = 10
+
+// findDuplicateKeys lists the key values more than one document of the
+// collection has for the index, in the scope of its partial filter, sparse
+// option and collation.
+func findDuplicateKeys(ctx context.Context, collectionClient *mongo.Collection, data *schema.ResourceData) ([]string, error) {
+	var fields []string
+	group := bson.D{}
+	for index, _key := range data.Get("keys").([]interface{}) {
+		field := _key.(map[string]interface{})["field"].(string)
+		fields = append(fields, field)
+		// missing fields are indexed as null, and dotted paths aren't valid
+		// group keys
+		group = append(group, bson.E{Key: fmt.Sprintf("k%d", index), Value: bson.D{{Key: "$ifNull", Value: bson.A{"$" + field, nil}}}})
+	}
+
+	pipeline := bson.A{}
+	if partialFilter := data.Get("partial_filter_expression").(string); len(partialFilter) > 0 {
+		var filterDoc bson.D
+		if err := bson.UnmarshalExtJSON([]byte(partialFilter), false, &filterDoc); err != nil {
+			return nil, err
+		}
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: filterDoc}})
+	}
+	if data.Get("sparse").(bool) {
+		// a sparse index leaves out documents missing all of its fields
+		exists := bson.A{}
+		for _, field := range fields {
+			exists = append(exists, bson.D{{Key: field, Value: bson.D{{Key: "$exists", Value: true}}}})
+		}
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: bson.D{{Key: "$or", Value: exists}}}})
+	}
+	// every element of an array is a key of its own, but a document repeating
+	// one in an array doesn't conflict with itself
+	for _, field := range fields {
+		pipeline = append(pipeline, bson.D{{Key: "$unwind", Value: bson.D{{Key: "path", Value: "$" + field}, {Key: "preserveNullAndEmptyArrays", Value: true}}}})
+	}
+	pipeline = append(pipeline,
+		bson.D{{Key: "$group", Value: bson.D{{Key: "_id", Value: bson.D{{Key: "key", Value: group}, {Key: "document", Value: "$_id"}}}}}},
+		bson.D{{Key: "$group", Value: bson.D{{Key: "_id", Value: "$_id.key"}, {Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}}}}},
+		bson.D{{Key: "$match", Value: bson.D{{Key: "count", Value: bson.D{{Key: "$gt", Value: 1}}}}}},
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}}}},
+		bson.D{{Key: "$limit", Value: maxDuplicateKeys}},
+	)
+
+	aggregateOptions := options.Aggregate()
+	if collation := expandCollation(data.Get("collation").([]interface{})); collation != nil {
+		aggregateOptions.SetCollation(collation)
+	}
+	cursor, err := collectionClient.Aggregate(ctx, pipeline, aggregateOptions)
+	if err != nil {
+		return nil, err
+	}
+	var groups []struct {
+		ID    bson.D `json:"_id" bson:"_id"`
+		Count int    `json:"count" bson:"count"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		return nil, err
+	}
+
+	var duplicates []string
+	for _, duplicate := range groups {
+		key := bson.D{}
+		for index, elem := range duplicate.ID {
+			key = append(key, bson.E{Key: fields[index], Value: elem.Value})
+		}
+		keyJSON, err := bson.MarshalExtJSON(key, false, false)
+		if err != nil {
+			return nil, err
+		}
+		duplicates = append(duplicates, fmt.Sprintf("%s (%d documents)", keyJSON, duplicate.Count))
+	}
+	return duplicates, nil
+}
+
+func createIndex(ctx context.Context, client *mongo.Client, db string, collectionName string, data *schema.ResourceData, timeout time.Duration) (string, diag.Diagnostics) {
 	collectionClient := client.Database(db).Collection(collectionName)
 
 	var keys = data.Get("keys").([]interface{})
@@ -302,43 +731,62 @@ func createIndex(client *mongo.Client, db string, collectionName string, data *s
 		key := _key.(map[string]interface{})
 		keyField := key["field"].(string)
 		value := key["value"].(string)
+		indexKeys = append(indexKeys, bson.E{Key: keyField, Value: expandIndexKeyValue(value)})
+	}
 
-		if keyField == "expireAfterSeconds" {
-			valueInt, err := strconv.Atoi(value)
-			if err != nil {
-				return "", diag.Errorf("expireAfterSeconds value must be integer : %s ", err)
-			}
-			if valueInt >= 0 {
-				indexOptions.SetExpireAfterSeconds(int32(valueInt))
-				continue
-			}
+	if unique := data.Get("unique").(bool); unique {
+		indexOptions.SetUnique(true)
+	}
+	if sparse := data.Get("sparse").(bool); sparse {
+		indexOptions.SetSparse(true)
+	}
+	if expireAfterSeconds := data.Get("expire_after_seconds").(int); expireAfterSeconds >= 0 {
+		indexOptions.SetExpireAfterSeconds(int32(expireAfterSeconds))
+	}
+	if bits, ok := data.GetOk("bits"); ok {
+		indexOptions.SetBits(int32(bits.(int)))
+	}
+	// 0 is a valid bound, so only the configuration tells whether they are set
+	if isConfigured(data, "min") {
+		indexOptions.SetMin(data.Get("min").(float64))
+	}
+	if isConfigured(data, "max") {
+		indexOptions.SetMax(data.Get("max").(float64))
+	}
+	if weights := data.Get("weights").(map[string]interface{}); len(weights) > 0 {
+		fields := make([]string, 0, len(weights))
+		for field := range weights {
+			fields = append(fields, field)
 		}
-
-		if strings.ToLower(keyField) == "unique" && (strings.ToLower(value) == "true" || strings.ToLower(value) == "false") {
-			indexOptions.SetUnique(strings.ToLower(value) == "true")
-			continue
-		} else if value == "1" {
-			indexKeys = append(indexKeys, bson.E{Key: keyField, Value: 1})
-		} else if value == "-1" {
-			indexKeys = append(indexKeys, bson.E{Key: keyField, Value: -1})
-		} else if value == "true" {
-			indexKeys = append(indexKeys, bson.E{Key: keyField, Value: true})
-		} else if value == "false" {
-			indexKeys = append(indexKeys, bson.E{Key: keyField, Value: false})
-		} else {
-			indexKeys = append(indexKeys, bson.E{Key: keyField, Value: value})
+		sort.Strings(fields)
+		weightsDoc := bson.D{}
+		for _, field := range fields {
+			weightsDoc = append(weightsDoc, bson.E{Key: field, Value: weights[field]})
 		}
+		indexOptions.SetWeights(weightsDoc)
 	}
-
-	//indexOptions.SetUnique(data.Get("unique").(bool))
-	//indexOptions.SetSparse(data.Get("sparse").(bool))
-	//indexOptions.SetBits(int32(data.Get("bits").(int)))
-	//indexOptions.SetMin(data.Get("min").(float64))
-	//indexOptions.SetMax(data.Get("max").(float64))
+	if defaultLanguage := data.Get("default_language").(string); len(defaultLanguage) > 0 {
+		indexOptions.SetDefaultLanguage(defaultLanguage)
+	}
+	if languageOverride := data.Get("language_override").(string); len(languageOverride) > 0 {
+		indexOptions.SetLanguageOverride(languageOverride)
+	}
+	if projection := data.Get("wildcard_projection").(string); len(projection) > 0 {
+		var projectionDoc bson.D
+		if err := bson.UnmarshalExtJSON([]byte(projection), false, &projectionDoc); err != nil {
+			return "", diag.Errorf("Invalid wildcard_projection JSON: %s", err)
+		}
+		indexOptions.SetWildcardProjection(projectionDoc)
+	}
+	if version, ok := data.GetOk("index_2dsphere_version"); ok {
+		indexOptions.SetSphereVersion(int32(version.(int)))
+	}
+	// The build is followed by name, so it is named like the driver would
 	var name = data.Get("name").(string)
-	if len(name) > 0 {
-		indexOptions.SetName(name)
+	if len(name) == 0 {
+		name = generateIndexName(indexKeys)
 	}
+	indexOptions.SetName(name)
 
 	// Handle partialFilterExpression
 	if partialFilter := data.Get("partial_filter_expression").(string); len(partialFilter) > 0 {
@@ -349,6 +797,10 @@ func createIndex(client *mongo.Client, db string, collectionName string, data *s
 		indexOptions.SetPartialFilterExpression(filterDoc)
 	}
 
+	if collation := expandCollation(data.Get("collation").([]interface{})); collation != nil {
+		indexOptions.SetCollation(collation)
+	}
+
 	// Handle hidden
 	if hidden := data.Get("hidden").(bool); hidden {
 		indexOptions.SetHidden(true)
@@ -360,17 +812,397 @@ func createIndex(client *mongo.Client, db string, collectionName string, data *s
 		Options: indexOptions,
 	}
 
+	createOptions := options.CreateIndexes()
+	if commitQuorum := data.Get("commit_quorum").(string); len(commitQuorum) > 0 {
+		if members, err := strconv.Atoi(commitQuorum); err == nil {
+			createOptions.SetCommitQuorumInt(int32(members))
+		} else {
+			createOptions.SetCommitQuorumString(commitQuorum)
+		}
+	}
+
 	// In MongoDB driver v2, MaxTime option is removed. Use context timeout instead.
-	var timeout = data.Get("timeout").(int)
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	// Create the index
-	indexName, err := collectionClient.Indexes().CreateOne(ctx, indexModel)
+	err := buildIndex(ctx, collectionClient, indexModel, createOptions, name)
+	var serverError mongo.ServerError
+	if err != nil && errors.As(err, &serverError) && serverError.HasErrorCode(indexKeySpecsConflictCode) {
+		return "", diag.Errorf("Could not create the index : %s, an index named %s exists with other keys, it is not adopted ", err, name)
+	}
+	if err != nil && errors.As(err, &serverError) && serverError.HasErrorCode(indexOptionsConflictCode) {
+		if !data.Get("adopt_existing").(bool) {
+			return "", diag.Errorf("Could not create the index : %s, set adopt_existing to take over the existing index ", err)
+		}
+		adoptedName, diags := adoptIndex(ctx, collectionClient, data)
+		if diags != nil || adoptedName != "" {
+			return adoptedName, diags
+		}
+		err = buildIndex(ctx, collectionClient, indexModel, createOptions, name)
+	}
 	if err != nil {
 		return "", diag.Errorf("Could not create the index : %s ", err)
 	}
-	return indexName, nil
+	return name, nil
+}
+
+// adoptIndex takes over the index conflicting with the configured one and
+// returns its name. Options collMod can change are changed in place; when
+// others differ the index is dropped and "" is returned, so that the
+// configured index is built instead, if adopt_rebuild allows it.
+func adoptIndex(ctx context.Context, collectionClient *mongo.Collection, data *schema.ResourceData) (string, diag.Diagnostics) {
+	existing, err := findEquivalentIndex(ctx, collectionClient, data)
+	if err != nil {
+		return "", diag.Errorf("Failed to list indexes: %s", err)
+	}
+	if existing == nil {
+		return "", diag.Errorf("Could not create the index : an index conflicts with it, but none has the same keys, partial_filter_expression and collation to adopt")
+	}
+	existingName := existing["name"].(string)
+
+	inPlace, rebuild := indexOptionDifferences(existing, data)
+	if len(rebuild) > 0 {
+		if !data.Get("adopt_rebuild").(bool) {
+			return "", diag.Errorf("Could not adopt index %s : its %s differ from the configuration and can't be changed in place, "+
+				"change the configuration to match or set adopt_rebuild to drop and rebuild it", existingName, strings.Join(rebuild, ", "))
+		}
+		tflog.Warn(ctx, fmt.Sprintf("Rebuilding index %s, its %s differ from the configuration", existingName, strings.Join(rebuild, ", ")))
+		err := collectionClient.Indexes().DropOne(ctx, existingName)
+		if err != nil {
+			return "", diag.Errorf("Could not drop index %s : %s ", existingName, err)
+		}
+		return "", nil
+	}
+
+	tflog.Info(ctx, fmt.Sprintf("Adopting index %s on %s.%s", existingName, collectionClient.Database().Name(), collectionClient.Name()))
+	dbClient := collectionClient.Database()
+	for _, option := range inPlace {
+		switch option {
+		case "hidden":
+			err = modifyIndex(dbClient, collectionClient.Name(), existingName, bson.E{Key: "hidden", Value: data.Get("hidden").(bool)})
+		case "expire_after_seconds":
+			err = modifyIndex(dbClient, collectionClient.Name(), existingName, bson.E{Key: "expireAfterSeconds", Value: int64(data.Get("expire_after_seconds").(int))})
+		case "unique":
+			if diags := convertIndexToUnique(ctx, dbClient, collectionClient.Name(), existingName, data); diags != nil {
+				return "", diags
+			}
+		}
+		if err != nil {
+			return "", diag.Errorf("Failed to update the %s of index %s: %s", option, existingName, err)
+		}
+	}
+	if name := data.Get("name").(string); len(name) > 0 && name != existingName {
+		_ = data.Set("requested_name", name)
+	}
+	return existingName, nil
+}
+
+// findEquivalentIndex returns the listIndexes entry with the key pattern,
+// partial filter and collation of the configured index, which together
+// identify an index on the server.
+func findEquivalentIndex(ctx context.Context, collectionClient *mongo.Collection, data *schema.ResourceData) (bson.M, error) {
+	cursor, err := collectionClient.Indexes().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var results []bson.M
+	if err = cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	keys := data.Get("keys").([]interface{})
+	partialFilter, _ := normalizeExtJSON(data.Get("partial_filter_expression").(string))
+	collation := data.Get("collation").([]interface{})
+	for _, result := range results {
+		key, _ := result["key"].(bson.D)
+		weights, _ := result["weights"].(bson.D)
+		if !reflect.DeepEqual(flattenIndexKeys(key, weights, keys), keys) {
+			continue
+		}
+
+		existingFilter := ""
+		if document, ok := result["partialFilterExpression"]; ok {
+			if filterBytes, err := bson.MarshalExtJSON(document, false, false); err == nil {
+				existingFilter, _ = normalizeExtJSON(string(filterBytes))
+			}
+		}
+		if existingFilter != partialFilter {
+			continue
+		}
+
+		if len(collation) > 0 {
+			var existingCollation bson.Raw
+			if document, ok := result["collation"]; ok {
+				existingCollation, _ = bson.Marshal(document)
+			}
+			if !sameCollation(flattenCollation(existingCollation, collation), collation) {
+				continue
+			}
+		}
+		return result, nil
+	}
+	return nil, nil
+}
+
+// sameCollation tells whether an index collation has the fields set in the
+// configured one, fields left out of the configuration take any value.
+func sameCollation(existing []interface{}, configured []interface{}) bool {
+	if len(existing) == 0 || len(configured) == 0 || configured[0] == nil {
+		return len(existing) == len(configured)
+	}
+	existingFields := existing[0].(map[string]interface{})
+	for field, value := range configured[0].(map[string]interface{}) {
+		if value == "" || value == false || value == 0 {
+			continue
+		}
+		if existingFields[field] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// indexOptionDifferences lists the options of an existing index that differ
+// from the configuration, split into those collMod changes in place and
+// those needing a new index.
+func indexOptionDifferences(existing bson.M, data *schema.ResourceData) ([]string, []string) {
+	var inPlace, rebuild []string
+
+	unique := existing["unique"] == true
+	if unique && !data.Get("unique").(bool) {
+		rebuild = append(rebuild, "unique")
+	} else if !unique && data.Get("unique").(bool) {
+		inPlace = append(inPlace, "unique")
+	}
+	if (existing["sparse"] == true) != data.Get("sparse").(bool) {
+		rebuild = append(rebuild, "sparse")
+	}
+	if (existing["hidden"] == true) != data.Get("hidden").(bool) {
+		inPlace = append(inPlace, "hidden")
+	}
+
+	expireAfterSeconds := -1
+	if seconds, ok := indexNumber(existing["expireAfterSeconds"]); ok {
+		expireAfterSeconds = int(seconds)
+	}
+	if configured := data.Get("expire_after_seconds").(int); configured != expireAfterSeconds {
+		if configured < 0 || (expireAfterSeconds < 0 && len(data.Get("keys").([]interface{})) != 1) {
+			rebuild = append(rebuild, "expire_after_seconds")
+		} else {
+			inPlace = append(inPlace, "expire_after_seconds")
+		}
+	}
+
+	numbers := map[string]string{"bits": "bits", "min": "min", "max": "max", "index_2dsphere_version": "2dsphereIndexVersion"}
+	for attribute, option := range numbers {
+		if !isConfigured(data, attribute) {
+			continue
+		}
+		var configured float64
+		switch value := data.Get(attribute).(type) {
+		case int:
+			configured = float64(value)
+		case float64:
+			configured = value
+		}
+		if existingValue, ok := indexNumber(existing[option]); !ok || existingValue != configured {
+			rebuild = append(rebuild, attribute)
+		}
+	}
+	for attribute, option := range map[string]string{"default_language": "default_language", "language_override": "language_override"} {
+		if configured := data.Get(attribute).(string); len(configured) > 0 && existing[option] != configured {
+			rebuild = append(rebuild, attribute)
+		}
+	}
+	if weights := data.Get("weights").(map[string]interface{}); len(weights) > 0 {
+		existingWeights, _ := existing["weights"].(bson.D)
+		if !reflect.DeepEqual(flattenIndexWeights(existingWeights, weights), weights) {
+			rebuild = append(rebuild, "weights")
+		}
+	}
+
+	existingProjection := ""
+	if document, ok := existing["wildcardProjection"]; ok {
+		if projectionBytes, err := bson.MarshalExtJSON(document, false, false); err == nil {
+			existingProjection, _ = normalizeExtJSON(string(projectionBytes))
+		}
+	}
+	if projection, _ := normalizeExtJSON(data.Get("wildcard_projection").(string)); projection != existingProjection {
+		rebuild = append(rebuild, "wildcard_projection")
+	}
+
+	sort.Strings(rebuild)
+	return inPlace, rebuild
+}
+
+// indexBuildPollInterval is how often the progress of an index build is
+// looked up.
+const indexBuildPollInterval = 10 * time.Second
+
+// buildIndex starts the build of an index and follows its progress until it
+// is ready. The createIndexes command only replies once the build finished,
+// which can take long enough on large collections for the connection to be
+// lost; the build continues on the server and is then followed through
+// listIndexes.
+func buildIndex(ctx context.Context, collectionClient *mongo.Collection, indexModel mongo.IndexModel, createOptions *options.CreateIndexesOptionsBuilder, indexName string) error {
+	done := make(chan error, 1)
+	go func() {
+		_, err := collectionClient.Indexes().CreateOne(ctx, indexModel, createOptions)
+		done <- err
+	}()
+
+	ticker := time.NewTicker(indexBuildPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case err := <-done:
+			if err == nil || ctx.Err() != nil || !(mongo.IsNetworkError(err) || mongo.IsTimeout(err)) {
+				return indexBuildError(ctx, indexName, err)
+			}
+			tflog.Warn(ctx, fmt.Sprintf("Lost the reply of the build of index %s, following it until it is ready : %s", indexName, err))
+			return waitForIndexBuild(ctx, collectionClient, indexName, ticker)
+		case <-ticker.C:
+			logIndexBuildProgress(ctx, collectionClient, indexName)
+		}
+	}
+}
+
+// waitForIndexBuild polls listIndexes until the index is ready, failing when
+// its build is no longer running without the index being ready.
+func waitForIndexBuild(ctx context.Context, collectionClient *mongo.Collection, indexName string, ticker *time.Ticker) error {
+	for {
+		exists, err := indexExists(collectionClient.Database().Client(), collectionClient.Database().Name(), collectionClient.Name(), indexName)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return nil
+		}
+		if !logIndexBuildProgress(ctx, collectionClient, indexName) {
+			return fmt.Errorf("the build of index %s stopped before it was ready", indexName)
+		}
+		select {
+		case <-ctx.Done():
+			return indexBuildError(ctx, indexName, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+func indexBuildError(ctx context.Context, indexName string, err error) error {
+	if err != nil && ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("index %s was not ready in time, its build may still be running on the server, "+
+			"the next apply waits for it again; raise the create timeout of the timeouts block for large collections : %s", indexName, err)
+	}
+	return err
+}
+
+// logIndexBuildProgress logs the progress currentOp reports for the build of
+// the index, and whether the build is running.
+func logIndexBuildProgress(ctx context.Context, collectionClient *mongo.Collection, indexName string) bool {
+	namespace := collectionClient.Database().Name() + "." + collectionClient.Name()
+	result := collectionClient.Database().Client().Database("admin").RunCommand(ctx, bson.D{
+		{Key: "currentOp", Value: true},
+		{Key: "command.createIndexes", Value: collectionClient.Name()},
+		{Key: "command.indexes.name", Value: indexName},
+	})
+	var currentOp struct {
+		InProgress []struct {
+			Namespace string `json:"ns" bson:"ns"`
+			Message   string `json:"msg" bson:"msg"`
+		} `json:"inprog" bson:"inprog"`
+	}
+	if err := result.Decode(&currentOp); err != nil {
+		// without the inprog privilege the build is followed blindly
+		tflog.Debug(ctx, fmt.Sprintf("Could not look up the build of index %s : %s", indexName, err))
+		return true
+	}
+
+	running := false
+	for _, operation := range currentOp.InProgress {
+		// the build itself runs on the collection, the command on the database
+		if operation.Namespace != namespace && operation.Namespace != collectionClient.Database().Name()+".$cmd" {
+			continue
+		}
+		running = true
+		if len(operation.Message) > 0 {
+			tflog.Info(ctx, fmt.Sprintf("Building index %s on %s: %s", indexName, namespace, operation.Message))
+			return true
+		}
+	}
+	if running {
+		tflog.Info(ctx, fmt.Sprintf("Building index %s on %s", indexName, namespace))
+	}
+	return running
+}
+
+// generateIndexName names an index the way the driver and the shell do,
+// e.g. field_1_other_-1.
+func generateIndexName(indexKeys bson.D) string {
+	var parts []string
+	for _, elem := range indexKeys {
+		parts = append(parts, fmt.Sprintf("%s_%v", elem.Key, elem.Value))
+	}
+	return strings.Join(parts, "_")
+}
+
+// moveIndex points the index at its collection's new namespace. A rename
+// with renameCollection carries the indexes along, so usually only the ID
+// changes; otherwise the index is built on the new collection and dropped
+// from the old one if it still exists.
+func moveIndex(ctx context.Context, data *schema.ResourceData, i interface{}) diag.Diagnostics {
+	var config = i.(*MongoDatabaseConfiguration)
+	client, connectionError := MongoClientInit(config)
+	if connectionError != nil {
+		return diag.Errorf("Error connecting to database : %s ", connectionError)
+	}
+
+	db, collectionName, indexName, err := resourceDatabaseIndexParseId(data.State().ID)
+	if err != nil {
+		return diag.Errorf("%s", err)
+	}
+	var newDb = data.Get("db").(string)
+	var newCollectionName = data.Get("collection").(string)
+
+	exists, err := indexExists(client, newDb, newCollectionName, indexName)
+	if err != nil {
+		return diag.Errorf("Failed to list indexes: %s", err)
+	}
+	if exists {
+		tflog.Info(ctx, fmt.Sprintf("Index %s moved with its collection to %s.%s", indexName, newDb, newCollectionName))
+	} else {
+		tflog.Info(ctx, fmt.Sprintf("Building index %s on %s.%s", indexName, newDb, newCollectionName))
+		newIndexName, diags := createIndex(ctx, client, newDb, newCollectionName, data, data.Timeout(schema.TimeoutUpdate))
+		if diags != nil {
+			return diags
+		}
+		err = client.Database(db).Collection(collectionName).Indexes().DropOne(context.Background(), indexName)
+		var serverError mongo.ServerError
+		if err != nil && !(errors.As(err, &serverError) && (serverError.HasErrorCode(namespaceNotFoundCode) || serverError.HasErrorCode(indexNotFoundCode))) {
+			return diag.Errorf("Could not drop the index from %s.%s : %s ", db, collectionName, err)
+		}
+		indexName = newIndexName
+	}
+
+	SetId(data, []string{newDb, newCollectionName, indexName})
+	return nil
+}
+
+func indexExists(client *mongo.Client, db string, collectionName string, indexName string) (bool, error) {
+	specifications, err := client.Database(db).Collection(collectionName).Indexes().ListSpecifications(context.Background())
+	if err != nil {
+		var serverError mongo.ServerError
+		if errors.As(err, &serverError) && serverError.HasErrorCode(namespaceNotFoundCode) {
+			return false, nil
+		}
+		return false, err
+	}
+	for _, specification := range specifications {
+		if specification.Name == indexName {
+			return true, nil
+		}
+	}
+	return false, nil
 }
 
 func dropIndex(client *mongo.Client, db string, collectionName string, indexName string) diag.Diagnostics {
@@ -385,6 +1217,30 @@ func dropIndex(client *mongo.Client, db string, collectionName string, indexName
 	return nil
 }
 
+// isConfigured tells whether an attribute is set in the configuration.
+func isConfigured(data *schema.ResourceData, attribute string) bool {
+	config := data.GetRawConfig()
+	if config.IsNull() || !config.IsKnown() {
+		return false
+	}
+	value := config.GetAttr(attribute)
+	return value.IsKnown() && !value.IsNull()
+}
+
+// indexNumber converts a numeric index option, which the server may return
+// as any BSON number type.
+func indexNumber(value interface{}) (float64, bool) {
+	switch number := value.(type) {
+	case int32:
+		return float64(number), true
+	case int64:
+		return float64(number), true
+	case float64:
+		return number, true
+	}
+	return 0, false
+}
+
 func resourceDatabaseIndexParseId(id string) (string, string, string, error) {
 	parts, err := ParseId(id, 3)
 	if err != nil {
@@ -0,0 +1,112 @@
+package mongodb
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// resourceDatabaseIndexV0 is the index schema before the index options got
+// their own attributes, when unique and expireAfterSeconds were given as
+// entries of keys.
+func resourceDatabaseIndexV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"db": {
+				Type:     schema.TypeString,
+				ForceNew: true,
+				Required: true,
+			},
+			"collection": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"keys": {
+				Type:     schema.TypeList,
+				Required: true,
+				ForceNew: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"field": {
+							Type:     schema.TypeString,
+							ForceNew: true,
+							Required: true,
+						},
+						"value": {
+							Type:     schema.TypeString,
+							ForceNew: true,
+							Required: true,
+						},
+					},
+				},
+			},
+			"name": {
+				Type:     schema.TypeString,
+				ForceNew: true,
+				Optional: true,
+				Default:  "",
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					if len(old) > 0 && len(new) == 0 {
+						return true
+					}
+					return false
+				},
+			},
+			"partial_filter_expression": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "",
+				Description: "A JSON string representing the partialFilterExpression for a partial index. Example: {\"field\": {\"$exists\": true}}",
+			},
+			"hidden": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "If true, the index is hidden from the query planner (MongoDB 4.4+). Can be toggled without recreating the index.",
+			},
+			"timeout": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  30,
+			},
+		},
+	}
+}
+
+// resourceDatabaseIndexStateUpgradeV0 moves the unique and expireAfterSeconds
+// entries out of keys into the attributes replacing them, and stores "true"
+// and "false" keys values as the "1" they were created as.
+func resourceDatabaseIndexStateUpgradeV0(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	if rawState == nil {
+		return rawState, nil
+	}
+	rawState["unique"] = false
+	rawState["expire_after_seconds"] = -1
+
+	keys, _ := rawState["keys"].([]interface{})
+	indexKeys := make([]interface{}, 0, len(keys))
+	for _, _key := range keys {
+		key, ok := _key.(map[string]interface{})
+		if !ok {
+			indexKeys = append(indexKeys, _key)
+			continue
+		}
+		field, _ := key["field"].(string)
+		value, _ := key["value"].(string)
+		switch magicIndexKeyAttribute(field, value) {
+		case "unique":
+			rawState["unique"] = strings.ToLower(value) == "true"
+		case "expire_after_seconds":
+			rawState["expire_after_seconds"], _ = strconv.Atoi(value)
+		default:
+			key["value"] = legacyIndexKeyValue(value)
+			indexKeys = append(indexKeys, key)
+		}
+	}
+	rawState["keys"] = indexKeys
+	return rawState, nil
+}
@@ -0,0 +1,101 @@
+package mongodb
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestResourceDatabaseIndexStateUpgradeV0(t *testing.T) {
+	cases := []struct {
+		name     string
+		keys     []interface{}
+		expected map[string]interface{}
+	}{
+		{
+			name: "plain keys",
+			keys: []interface{}{
+				map[string]interface{}{"field": "created_at", "value": "1"},
+			},
+			expected: map[string]interface{}{
+				"keys": []interface{}{
+					map[string]interface{}{"field": "created_at", "value": "1"},
+				},
+				"unique":               false,
+				"expire_after_seconds": -1,
+			},
+		},
+		{
+			name: "ttl",
+			keys: []interface{}{
+				map[string]interface{}{"field": "created_at", "value": "1"},
+				map[string]interface{}{"field": "expireAfterSeconds", "value": "3600"},
+			},
+			expected: map[string]interface{}{
+				"keys": []interface{}{
+					map[string]interface{}{"field": "created_at", "value": "1"},
+				},
+				"unique":               false,
+				"expire_after_seconds": 3600,
+			},
+		},
+		{
+			name: "unique",
+			keys: []interface{}{
+				map[string]interface{}{"field": "entity_type", "value": "1"},
+				map[string]interface{}{"field": "entity_id", "value": "-1"},
+				map[string]interface{}{"field": "Unique", "value": "TRUE"},
+			},
+			expected: map[string]interface{}{
+				"keys": []interface{}{
+					map[string]interface{}{"field": "entity_type", "value": "1"},
+					map[string]interface{}{"field": "entity_id", "value": "-1"},
+				},
+				"unique":               true,
+				"expire_after_seconds": -1,
+			},
+		},
+		{
+			name: "fields named like options",
+			keys: []interface{}{
+				map[string]interface{}{"field": "unique", "value": "1"},
+				map[string]interface{}{"field": "expireAfterSeconds", "value": "-1"},
+			},
+			expected: map[string]interface{}{
+				"keys": []interface{}{
+					map[string]interface{}{"field": "unique", "value": "1"},
+					map[string]interface{}{"field": "expireAfterSeconds", "value": "-1"},
+				},
+				"unique":               false,
+				"expire_after_seconds": -1,
+			},
+		},
+		{
+			name: "boolean values",
+			keys: []interface{}{
+				map[string]interface{}{"field": "active", "value": "true"},
+				map[string]interface{}{"field": "archived", "value": "false"},
+			},
+			expected: map[string]interface{}{
+				"keys": []interface{}{
+					map[string]interface{}{"field": "active", "value": "1"},
+					map[string]interface{}{"field": "archived", "value": "1"},
+				},
+				"unique":               false,
+				"expire_after_seconds": -1,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := resourceDatabaseIndexStateUpgradeV0(context.Background(), map[string]interface{}{"keys": tc.keys}, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !reflect.DeepEqual(actual, tc.expected) {
+				t.Fatalf("expected %v, got %v", tc.expected, actual)
+			}
+		})
+	}
+}
@@ -3,12 +3,17 @@ package mongodb
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"regexp"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
 func TestAccMongoDBIndex_Basic(t *testing.T) {
@@ -128,11 +133,10 @@ func TestAccMongoDBIndex_TTLIndex(t *testing.T) {
 					resource.TestCheckResourceAttr(resourceName, "db", databaseName),
 					resource.TestCheckResourceAttr(resourceName, "collection", collectionName),
 					resource.TestCheckResourceAttr(resourceName, "name", indexName),
-					resource.TestCheckResourceAttr(resourceName, "keys.#", "2"),
+					resource.TestCheckResourceAttr(resourceName, "keys.#", "1"),
 					resource.TestCheckResourceAttr(resourceName, "keys.0.field", "created_at"),
 					resource.TestCheckResourceAttr(resourceName, "keys.0.value", "1"),
-					resource.TestCheckResourceAttr(resourceName, "keys.1.field", "expireAfterSeconds"),
-					resource.TestCheckResourceAttr(resourceName, "keys.1.value", "3600"),
+					resource.TestCheckResourceAttr(resourceName, "expire_after_seconds", "3600"),
 				),
 			},
 			{
@@ -145,6 +149,235 @@ func TestAccMongoDBIndex_TTLIndex(t *testing.T) {
 	})
 }
 
+func TestAccMongoDBIndex_TTLUpdate(t *testing.T) {
+	var indexName = acctest.RandomWithPrefix("tf-acc-test")
+	var collectionName = acctest.RandomWithPrefix("tf-acc-test")
+	var databaseName = acctest.RandomWithPrefix("tf-acc-test")
+	resourceName := "mongodb_db_index.test"
+	var since time.Time
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckMongoDBIndexDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccMongoDBIndexExpireAfterSeconds(databaseName, collectionName, indexName, -1),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMongoDBIndexSince(resourceName, &since),
+					resource.TestCheckResourceAttr(resourceName, "expire_after_seconds", "-1"),
+				),
+			},
+			{
+				Config: testAccMongoDBIndexExpireAfterSeconds(databaseName, collectionName, indexName, 2592000),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMongoDBIndexSince(resourceName, &since),
+					resource.TestCheckResourceAttr(resourceName, "expire_after_seconds", "2592000"),
+				),
+			},
+			{
+				Config: testAccMongoDBIndexExpireAfterSeconds(databaseName, collectionName, indexName, 7776000),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMongoDBIndexSince(resourceName, &since),
+					resource.TestCheckResourceAttr(resourceName, "expire_after_seconds", "7776000"),
+				),
+			},
+			{
+				Config: testAccMongoDBIndexExpireAfterSeconds(databaseName, collectionName, indexName, -1),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMongoDBIndexExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "expire_after_seconds", "-1"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccMongoDBIndex_UniqueConversion(t *testing.T) {
+	var indexName = acctest.RandomWithPrefix("tf-acc-test")
+	var collectionName = acctest.RandomWithPrefix("tf-acc-test")
+	var databaseName = acctest.RandomWithPrefix("tf-acc-test")
+	resourceName := "mongodb_db_index.test"
+	var since time.Time
+
+	documents := func(t *testing.T) *mongo.Collection {
+		return testAccClient(t).Database(databaseName).Collection(collectionName)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckMongoDBIndexDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccMongoDBIndexUniqueEmail(databaseName, collectionName, indexName, false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMongoDBIndexSince(resourceName, &since),
+					resource.TestCheckResourceAttr(resourceName, "unique", "false"),
+				),
+			},
+			{
+				PreConfig: func() {
+					_, err := documents(t).InsertMany(context.Background(), []interface{}{
+						bson.D{{Key: "email", Value: "duplicate@example.com"}},
+						bson.D{{Key: "email", Value: "duplicate@example.com"}},
+						bson.D{{Key: "email", Value: "single@example.com"}},
+					})
+					if err != nil {
+						t.Fatalf("error inserting documents: %s", err)
+					}
+				},
+				Config:      testAccMongoDBIndexUniqueEmail(databaseName, collectionName, indexName, true),
+				ExpectError: regexp.MustCompile(`(?s)has duplicate keys.*"email": ?"duplicate@example.com"\} \(2 documents\)`),
+			},
+			{
+				PreConfig: func() {
+					_, err := documents(t).DeleteOne(context.Background(), bson.D{{Key: "email", Value: "duplicate@example.com"}})
+					if err != nil {
+						t.Fatalf("error deleting document: %s", err)
+					}
+				},
+				Config: testAccMongoDBIndexUniqueEmail(databaseName, collectionName, indexName, true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMongoDBIndexSince(resourceName, &since),
+					resource.TestCheckResourceAttr(resourceName, "unique", "true"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccMongoDBIndex_Build(t *testing.T) {
+	var collectionName = acctest.RandomWithPrefix("tf-acc-test")
+	var databaseName = acctest.RandomWithPrefix("tf-acc-test")
+	resourceName := "mongodb_db_index.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckMongoDBIndexDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccMongoDBIndexBuild(databaseName, collectionName, false, ""),
+			},
+			{
+				PreConfig: func() {
+					documents := make([]interface{}, 0, 50000)
+					for i := 0; i < 50000; i++ {
+						documents = append(documents, bson.D{{Key: "sequence", Value: i}, {Key: "group", Value: i % 100}})
+					}
+					collection := testAccClient(t).Database(databaseName).Collection(collectionName)
+					if _, err := collection.InsertMany(context.Background(), documents); err != nil {
+						t.Fatalf("error inserting documents: %s", err)
+					}
+				},
+				Config: testAccMongoDBIndexBuild(databaseName, collectionName, true, ""),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMongoDBIndexExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "name", "group_1_sequence_-1"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccMongoDBIndex_CommitQuorum(t *testing.T) {
+	var collectionName = acctest.RandomWithPrefix("tf-acc-test")
+	var databaseName = acctest.RandomWithPrefix("tf-acc-test")
+	resourceName := "mongodb_db_index.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			var hello struct {
+				SetName string `json:"setName" bson:"setName"`
+			}
+			if err := testAccClient(t).Database("admin").RunCommand(context.Background(), bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+				t.Fatalf("error running hello: %s", err)
+			}
+			if hello.SetName == "" {
+				t.Skip("commitQuorum needs a replica set")
+			}
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckMongoDBIndexDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccMongoDBIndexBuild(databaseName, collectionName, true, "majority"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMongoDBIndexExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "commit_quorum", "majority"),
+				),
+			},
+		},
+	})
+}
+
+func TestGenerateIndexName(t *testing.T) {
+	indexKeys := bson.D{{Key: "group", Value: 1}, {Key: "sequence", Value: -1}, {Key: "body", Value: "text"}}
+	if name := generateIndexName(indexKeys); name != "group_1_sequence_-1_body_text" {
+		t.Fatalf("unexpected index name %s", name)
+	}
+}
+
+func TestAccMongoDBIndex_AdoptExisting(t *testing.T) {
+	var collectionName = acctest.RandomWithPrefix("tf-acc-test")
+	var databaseName = acctest.RandomWithPrefix("tf-acc-test")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckMongoDBIndexDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccMongoDBIndexBuild(databaseName, collectionName, false, ""),
+			},
+			{
+				PreConfig: func() {
+					indexes := testAccClient(t).Database(databaseName).Collection(collectionName).Indexes()
+					_, err := indexes.CreateMany(context.Background(), []mongo.IndexModel{
+						{Keys: bson.D{{Key: "email", Value: 1}}, Options: options.Index().SetName("legacy_email")},
+						{Keys: bson.D{{Key: "code", Value: 1}}, Options: options.Index().SetName("legacy_code").SetSparse(true)},
+					})
+					if err != nil {
+						t.Fatalf("error creating indexes: %s", err)
+					}
+				},
+				Config:      testAccMongoDBIndexAdopt(databaseName, collectionName, "by_email", false, false),
+				ExpectError: regexp.MustCompile(`set adopt_existing to take over the existing index`),
+			},
+			{
+				Config:      testAccMongoDBIndexAdopt(databaseName, collectionName, "by_email", true, false),
+				ExpectError: regexp.MustCompile(`(?s)its sparse differ from the configuration.*set adopt_rebuild`),
+			},
+			{
+				Config: testAccMongoDBIndexAdopt(databaseName, collectionName, "by_email", true, true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMongoDBIndexExists("mongodb_db_index.adopted"),
+					testAccCheckMongoDBIndexExists("mongodb_db_index.rebuilt"),
+					resource.TestCheckResourceAttr("mongodb_db_index.adopted", "name", "legacy_email"),
+					resource.TestCheckResourceAttr("mongodb_db_index.adopted", "requested_name", "by_email"),
+					resource.TestCheckResourceAttr("mongodb_db_index.adopted", "hidden", "true"),
+					resource.TestCheckResourceAttr("mongodb_db_index.rebuilt", "name", "by_code"),
+					resource.TestCheckResourceAttr("mongodb_db_index.rebuilt", "sparse", "false"),
+				),
+			},
+			{
+				Config:   testAccMongoDBIndexAdopt(databaseName, collectionName, "by_email", true, true),
+				PlanOnly: true,
+			},
+			{
+				// renaming an adopted index replaces it under the new name
+				Config: testAccMongoDBIndexAdopt(databaseName, collectionName, "by_email_v2", true, true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMongoDBIndexExists("mongodb_db_index.adopted"),
+					resource.TestCheckResourceAttr("mongodb_db_index.adopted", "name", "by_email_v2"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccMongoDBIndex_GeneratedName(t *testing.T) {
 	var collectionName = acctest.RandomWithPrefix("tf-acc-coll")
 	var databaseName = acctest.RandomWithPrefix("tf-acc-db")
@@ -188,15 +421,14 @@ func TestAccMongoDBIndex_Unique(t *testing.T) {
 					resource.TestCheckResourceAttr(resourceName, "db", databaseName),
 					resource.TestCheckResourceAttr(resourceName, "collection", collectionName),
 					resource.TestCheckResourceAttr(resourceName, "name", indexName),
-					resource.TestCheckResourceAttr(resourceName, "keys.#", "4"),
+					resource.TestCheckResourceAttr(resourceName, "keys.#", "3"),
 					resource.TestCheckResourceAttr(resourceName, "keys.0.field", "entity_type"),
 					resource.TestCheckResourceAttr(resourceName, "keys.0.value", "1"),
 					resource.TestCheckResourceAttr(resourceName, "keys.1.field", "entity_id"),
 					resource.TestCheckResourceAttr(resourceName, "keys.1.value", "1"),
 					resource.TestCheckResourceAttr(resourceName, "keys.2.field", "profile_type"),
 					resource.TestCheckResourceAttr(resourceName, "keys.2.value", "1"),
-					resource.TestCheckResourceAttr(resourceName, "keys.3.field", "unique"),
-					resource.TestCheckResourceAttr(resourceName, "keys.3.value", "true"),
+					resource.TestCheckResourceAttr(resourceName, "unique", "true"),
 				),
 			},
 			{
@@ -373,6 +605,238 @@ func TestAccMongoDBIndex_PartialFilterAndHidden(t *testing.T) {
 }
 
 // testAccCheckMongoDBIndexHasPartialFilter verifies the index has a partialFilterExpression in MongoDB
+func TestAccMongoDBIndex_Collation(t *testing.T) {
+	var collectionName = acctest.RandomWithPrefix("tf-acc-test")
+	var databaseName = acctest.RandomWithPrefix("tf-acc-test")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckMongoDBIndexDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccMongoDBIndexCollation(databaseName, collectionName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMongoDBIndexExists("mongodb_db_index.inherited"),
+					testAccCheckMongoDBIndexExists("mongodb_db_index.explicit"),
+					testAccCheckMongoDBIndexExists("mongodb_db_index.simple"),
+					resource.TestCheckResourceAttr("mongodb_db_collection.test", "collation.0.locale", "en"),
+					resource.TestCheckResourceAttr("mongodb_db_collection.test", "collation.0.strength", "2"),
+					resource.TestCheckResourceAttr("mongodb_db_index.inherited", "collation.0.locale", "en"),
+					resource.TestCheckResourceAttr("mongodb_db_index.inherited", "collation.0.strength", "2"),
+					resource.TestCheckResourceAttr("mongodb_db_index.explicit", "collation.0.locale", "fr"),
+					resource.TestCheckResourceAttr("mongodb_db_index.explicit", "collation.0.backwards", "true"),
+					resource.TestCheckResourceAttr("mongodb_db_index.explicit", "collation.0.numeric_ordering", "true"),
+					resource.TestCheckResourceAttr("mongodb_db_index.simple", "collation.0.locale", "simple"),
+				),
+			},
+			{
+				Config:   testAccMongoDBIndexCollation(databaseName, collectionName),
+				PlanOnly: true,
+			},
+			{
+				ResourceName:            "mongodb_db_index.explicit",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"timeout"},
+			},
+			{
+				ResourceName:            "mongodb_db_collection.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"deletion_protection"},
+			},
+		},
+	})
+}
+
+func TestAccMongoDBIndex_Options(t *testing.T) {
+	var collectionName = acctest.RandomWithPrefix("tf-acc-test")
+	var databaseName = acctest.RandomWithPrefix("tf-acc-test")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckMongoDBIndexDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccMongoDBIndexOptions(databaseName, collectionName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMongoDBIndexExists("mongodb_db_index.sparse"),
+					testAccCheckMongoDBIndexExists("mongodb_db_index.location"),
+					resource.TestCheckResourceAttr("mongodb_db_index.sparse", "sparse", "true"),
+					resource.TestCheckResourceAttr("mongodb_db_index.sparse", "unique", "true"),
+					resource.TestCheckResourceAttr("mongodb_db_index.sparse", "expire_after_seconds", "-1"),
+					resource.TestCheckResourceAttr("mongodb_db_index.location", "bits", "20"),
+					resource.TestCheckResourceAttr("mongodb_db_index.location", "min", "0"),
+					resource.TestCheckResourceAttr("mongodb_db_index.location", "max", "1000"),
+				),
+			},
+			{
+				Config:   testAccMongoDBIndexOptions(databaseName, collectionName),
+				PlanOnly: true,
+			},
+			{
+				ResourceName:            "mongodb_db_index.location",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"timeout"},
+			},
+		},
+	})
+}
+
+func TestAccMongoDBIndex_MagicKeys(t *testing.T) {
+	var collectionName = acctest.RandomWithPrefix("tf-acc-test")
+	var databaseName = acctest.RandomWithPrefix("tf-acc-test")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccMongoDBIndexMagicKey(databaseName, collectionName, "unique", "true"),
+				ExpectError: regexp.MustCompile(`set the unique attribute instead`),
+			},
+			{
+				Config:      testAccMongoDBIndexMagicKey(databaseName, collectionName, "expireAfterSeconds", "3600"),
+				ExpectError: regexp.MustCompile(`set the expire_after_seconds attribute instead`),
+			},
+		},
+	})
+}
+
+func TestAccMongoDBIndex_Text(t *testing.T) {
+	var collectionName = acctest.RandomWithPrefix("tf-acc-test")
+	var databaseName = acctest.RandomWithPrefix("tf-acc-test")
+	resourceName := "mongodb_db_index.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckMongoDBIndexDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccMongoDBIndexText(databaseName, collectionName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMongoDBIndexExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "keys.#", "3"),
+					resource.TestCheckResourceAttr(resourceName, "keys.0.field", "category"),
+					resource.TestCheckResourceAttr(resourceName, "keys.0.value", "1"),
+					resource.TestCheckResourceAttr(resourceName, "keys.1.field", "title"),
+					resource.TestCheckResourceAttr(resourceName, "keys.1.value", "text"),
+					resource.TestCheckResourceAttr(resourceName, "keys.2.field", "body"),
+					resource.TestCheckResourceAttr(resourceName, "keys.2.value", "text"),
+					resource.TestCheckResourceAttr(resourceName, "weights.%", "1"),
+					resource.TestCheckResourceAttr(resourceName, "weights.title", "10"),
+					resource.TestCheckResourceAttr(resourceName, "default_language", "french"),
+					resource.TestCheckResourceAttr(resourceName, "language_override", "language"),
+				),
+			},
+			{
+				Config:   testAccMongoDBIndexText(databaseName, collectionName),
+				PlanOnly: true,
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"timeout"},
+			},
+		},
+	})
+}
+
+func TestAccMongoDBIndex_Types(t *testing.T) {
+	var collectionName = acctest.RandomWithPrefix("tf-acc-test")
+	var databaseName = acctest.RandomWithPrefix("tf-acc-test")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckMongoDBIndexDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccMongoDBIndexTypes(databaseName, collectionName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMongoDBIndexExists("mongodb_db_index.sphere"),
+					testAccCheckMongoDBIndexExists("mongodb_db_index.hashed"),
+					testAccCheckMongoDBIndexExists("mongodb_db_index.wildcard"),
+					testAccCheckMongoDBIndexExists("mongodb_db_index.text_wildcard"),
+					resource.TestCheckResourceAttr("mongodb_db_index.sphere", "keys.0.value", "2dsphere"),
+					resource.TestCheckResourceAttr("mongodb_db_index.sphere", "index_2dsphere_version", "3"),
+					resource.TestCheckResourceAttr("mongodb_db_index.hashed", "keys.0.value", "hashed"),
+					resource.TestCheckResourceAttr("mongodb_db_index.wildcard", "keys.0.field", "$**"),
+					resource.TestCheckResourceAttr("mongodb_db_index.wildcard", "keys.0.value", "1"),
+					resource.TestCheckResourceAttr("mongodb_db_index.text_wildcard", "keys.#", "1"),
+					resource.TestCheckResourceAttr("mongodb_db_index.text_wildcard", "keys.0.field", "$**"),
+					resource.TestCheckResourceAttr("mongodb_db_index.text_wildcard", "keys.0.value", "text"),
+				),
+			},
+			{
+				Config:   testAccMongoDBIndexTypes(databaseName, collectionName),
+				PlanOnly: true,
+			},
+			{
+				ResourceName:            "mongodb_db_index.wildcard",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"timeout"},
+			},
+		},
+	})
+}
+
+func TestAccMongoDBIndex_InvalidKeys(t *testing.T) {
+	var collectionName = acctest.RandomWithPrefix("tf-acc-test")
+	var databaseName = acctest.RandomWithPrefix("tf-acc-test")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccMongoDBIndexMagicKey(databaseName, collectionName, "location", "geoHaystack"),
+				ExpectError: regexp.MustCompile(`must be one of 1, -1, text, 2d, 2dsphere, hashed`),
+			},
+			{
+				Config:      testAccMongoDBIndexMagicKey(databaseName, collectionName, "attributes.$**", "hashed"),
+				ExpectError: regexp.MustCompile(`must have a value of 1 or -1`),
+			},
+		},
+	})
+}
+
+func TestFlattenIndexKeys(t *testing.T) {
+	key := bson.D{{Key: "category", Value: int32(1)}, {Key: "_fts", Value: "text"}, {Key: "_ftsx", Value: int32(1)}, {Key: "score", Value: -1.0}}
+	weights := bson.D{{Key: "body", Value: int32(1)}, {Key: "title", Value: int32(10)}}
+	declared := []interface{}{
+		map[string]interface{}{"field": "category", "value": "1"},
+		map[string]interface{}{"field": "title", "value": "text"},
+		map[string]interface{}{"field": "body", "value": "text"},
+		map[string]interface{}{"field": "score", "value": "-1"},
+	}
+
+	if actual := flattenIndexKeys(key, weights, declared); !reflect.DeepEqual(actual, declared) {
+		t.Fatalf("expected %v, got %v", declared, actual)
+	}
+
+	imported := []interface{}{
+		map[string]interface{}{"field": "category", "value": "1"},
+		map[string]interface{}{"field": "body", "value": "text"},
+		map[string]interface{}{"field": "title", "value": "text"},
+		map[string]interface{}{"field": "score", "value": "-1"},
+	}
+	if actual := flattenIndexKeys(key, weights, nil); !reflect.DeepEqual(actual, imported) {
+		t.Fatalf("expected %v, got %v", imported, actual)
+	}
+
+	expected := map[string]interface{}{"title": 10}
+	if actual := flattenIndexWeights(weights, nil); !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected %v, got %v", expected, actual)
+	}
+}
+
 func testAccCheckMongoDBIndexHasPartialFilter(resourceName string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[resourceName]
@@ -471,6 +935,55 @@ func testAccCheckMongoDBIndexIsHidden(resourceName string, expectedHidden bool)
 	}
 }
 
+// testAccCheckMongoDBIndexSince records when the index statistics started,
+// which is when the index was built, and fails once the index was rebuilt.
+func testAccCheckMongoDBIndexSince(resourceName string, since *time.Time) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("not found: %s", resourceName)
+		}
+
+		config := testAccProvider.Meta().(*MongoDatabaseConfiguration)
+		client, err := MongoClientInit(config)
+		if err != nil {
+			return fmt.Errorf("error connecting to database: %s", err)
+		}
+
+		db, collectionName, indexName, err := resourceDatabaseIndexParseId(rs.Primary.ID)
+		if err != nil {
+			return fmt.Errorf("error parsing ID: %s", err)
+		}
+
+		cursor, err := client.Database(db).Collection(collectionName).Aggregate(context.Background(), bson.A{
+			bson.D{{Key: "$indexStats", Value: bson.D{}}},
+			bson.D{{Key: "$match", Value: bson.D{{Key: "name", Value: indexName}}}},
+		})
+		if err != nil {
+			return fmt.Errorf("error reading index statistics: %s", err)
+		}
+		var stats []struct {
+			Accesses struct {
+				Since time.Time `json:"since" bson:"since"`
+			} `json:"accesses" bson:"accesses"`
+		}
+		if err := cursor.All(context.Background(), &stats); err != nil {
+			return fmt.Errorf("error reading index statistics: %s", err)
+		}
+		if len(stats) != 1 {
+			return fmt.Errorf("index %s does not exist", indexName)
+		}
+
+		current := stats[0].Accesses.Since
+		if since.IsZero() {
+			*since = current
+		} else if !since.Equal(current) {
+			return fmt.Errorf("index %s was rebuilt instead of updated in place", indexName)
+		}
+		return nil
+	}
+}
+
 func testAccCheckMongoDBIndexExists(resourceName string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[resourceName]
@@ -653,11 +1166,8 @@ resource "mongodb_db_index" "test" {
     field = "created_at"
     value = "1"
   }
-  keys {
-    field = "expireAfterSeconds"
-    value = "3600"
-  }
-  timeout = 30
+  expire_after_seconds = 3600
+  timeout              = 30
 }
 `, dbName, collectionName, dbName, collectionName, indexName)
 }
@@ -708,10 +1218,7 @@ resource "mongodb_db_index" "test" {
     field = "profile_type"
     value = "1"
   }
-  keys {
-    field = "unique"
-    value = "true"
-  }
+  unique  = true
   timeout = 30
 }
 `, dbName, collectionName, dbName, collectionName, indexName)
@@ -831,3 +1338,301 @@ resource "mongodb_db_index" "test" {
 }
 `, dbName, collectionName, dbName, collectionName, indexName)
 }
+
+func testAccMongoDBIndexCollation(dbName, collectionName string) string {
+	return fmt.Sprintf(`
+resource "mongodb_db_collection" "test" {
+  db                  = "%s"
+  name                = "%s"
+  deletion_protection = false
+
+  collation {
+    locale   = "en"
+    strength = 2
+  }
+}
+
+resource "mongodb_db_index" "inherited" {
+  db         = mongodb_db_collection.test.db
+  collection = mongodb_db_collection.test.name
+  name       = "by_email"
+  keys {
+    field = "email"
+    value = "1"
+  }
+}
+
+resource "mongodb_db_index" "explicit" {
+  db         = mongodb_db_collection.test.db
+  collection = mongodb_db_collection.test.name
+  name       = "by_title"
+  keys {
+    field = "title"
+    value = "1"
+  }
+
+  collation {
+    locale           = "fr"
+    backwards        = true
+    numeric_ordering = true
+  }
+}
+
+resource "mongodb_db_index" "simple" {
+  db         = mongodb_db_collection.test.db
+  collection = mongodb_db_collection.test.name
+  name       = "by_code"
+  keys {
+    field = "code"
+    value = "1"
+  }
+
+  collation {
+    locale = "simple"
+  }
+}
+`, dbName, collectionName)
+}
+
+func testAccMongoDBIndexOptions(dbName, collectionName string) string {
+	return fmt.Sprintf(`
+resource "mongodb_db_collection" "test" {
+  db                  = "%s"
+  name                = "%s"
+  deletion_protection = false
+}
+
+resource "mongodb_db_index" "sparse" {
+  db         = mongodb_db_collection.test.db
+  collection = mongodb_db_collection.test.name
+  name       = "by_email"
+  unique     = true
+  sparse     = true
+  keys {
+    field = "email"
+    value = "1"
+  }
+}
+
+resource "mongodb_db_index" "location" {
+  db         = mongodb_db_collection.test.db
+  collection = mongodb_db_collection.test.name
+  name       = "by_location"
+  bits       = 20
+  min        = 0
+  max        = 1000
+  keys {
+    field = "location"
+    value = "2d"
+  }
+}
+`, dbName, collectionName)
+}
+
+func testAccMongoDBIndexMagicKey(dbName, collectionName, field, value string) string {
+	return fmt.Sprintf(`
+resource "mongodb_db_index" "test" {
+  db         = "%s"
+  collection = "%s"
+  keys {
+    field = "created_at"
+    value = "1"
+  }
+  keys {
+    field = "%s"
+    value = "%s"
+  }
+}
+`, dbName, collectionName, field, value)
+}
+
+func testAccMongoDBIndexText(dbName, collectionName string) string {
+	return fmt.Sprintf(`
+resource "mongodb_db_collection" "test" {
+  db                  = "%s"
+  name                = "%s"
+  deletion_protection = false
+}
+
+resource "mongodb_db_index" "test" {
+  db               = mongodb_db_collection.test.db
+  collection       = mongodb_db_collection.test.name
+  name             = "search"
+  default_language = "french"
+  weights = {
+    title = 10
+  }
+  keys {
+    field = "category"
+    value = "1"
+  }
+  keys {
+    field = "title"
+    value = "text"
+  }
+  keys {
+    field = "body"
+    value = "text"
+  }
+}
+`, dbName, collectionName)
+}
+
+func testAccMongoDBIndexTypes(dbName, collectionName string) string {
+	return fmt.Sprintf(`
+resource "mongodb_db_collection" "test" {
+  db                  = "%s"
+  name                = "%s"
+  deletion_protection = false
+}
+
+resource "mongodb_db_index" "sphere" {
+  db         = mongodb_db_collection.test.db
+  collection = mongodb_db_collection.test.name
+  name       = "by_location"
+  keys {
+    field = "location"
+    value = "2dsphere"
+  }
+}
+
+resource "mongodb_db_index" "hashed" {
+  db         = mongodb_db_collection.test.db
+  collection = mongodb_db_collection.test.name
+  name       = "by_tenant"
+  keys {
+    field = "tenant"
+    value = "hashed"
+  }
+}
+
+resource "mongodb_db_index" "wildcard" {
+  db                  = mongodb_db_collection.test.db
+  collection          = mongodb_db_collection.test.name
+  name                = "by_attributes"
+  wildcard_projection = jsonencode({ attributes = 1 })
+  keys {
+    field = "$**"
+    value = "1"
+  }
+}
+
+resource "mongodb_db_index" "text_wildcard" {
+  db         = mongodb_db_collection.test.db
+  collection = mongodb_db_collection.test.name
+  name       = "search_all"
+  keys {
+    field = "$**"
+    value = "text"
+  }
+}
+`, dbName, collectionName)
+}
+
+func testAccMongoDBIndexExpireAfterSeconds(dbName, collectionName, indexName string, expireAfterSeconds int) string {
+	return fmt.Sprintf(`
+resource "mongodb_db_collection" "test" {
+  db                  = "%s"
+  name                = "%s"
+  deletion_protection = false
+}
+
+resource "mongodb_db_index" "test" {
+  db                   = mongodb_db_collection.test.db
+  collection           = mongodb_db_collection.test.name
+  name                 = "%s"
+  expire_after_seconds = %d
+  keys {
+    field = "created_at"
+    value = "1"
+  }
+}
+`, dbName, collectionName, indexName, expireAfterSeconds)
+}
+
+func testAccMongoDBIndexUniqueEmail(dbName, collectionName, indexName string, unique bool) string {
+	return fmt.Sprintf(`
+resource "mongodb_db_collection" "test" {
+  db                  = "%s"
+  name                = "%s"
+  deletion_protection = false
+}
+
+resource "mongodb_db_index" "test" {
+  db         = mongodb_db_collection.test.db
+  collection = mongodb_db_collection.test.name
+  name       = "%s"
+  unique     = %t
+  keys {
+    field = "email"
+    value = "1"
+  }
+}
+`, dbName, collectionName, indexName, unique)
+}
+
+func testAccMongoDBIndexBuild(dbName, collectionName string, withIndex bool, commitQuorum string) string {
+	config := fmt.Sprintf(`
+resource "mongodb_db_collection" "test" {
+  db                  = "%s"
+  name                = "%s"
+  deletion_protection = false
+}
+`, dbName, collectionName)
+	if !withIndex {
+		return config
+	}
+	return config + fmt.Sprintf(`
+resource "mongodb_db_index" "test" {
+  db            = mongodb_db_collection.test.db
+  collection    = mongodb_db_collection.test.name
+  commit_quorum = %q
+  keys {
+    field = "group"
+    value = "1"
+  }
+  keys {
+    field = "sequence"
+    value = "-1"
+  }
+
+  timeouts {
+    create = "10m"
+  }
+}
+`, commitQuorum)
+}
+
+func testAccMongoDBIndexAdopt(dbName, collectionName, emailIndexName string, adopt bool, rebuild bool) string {
+	return fmt.Sprintf(`
+resource "mongodb_db_collection" "test" {
+  db                  = "%s"
+  name                = "%s"
+  deletion_protection = false
+}
+
+resource "mongodb_db_index" "adopted" {
+  db             = mongodb_db_collection.test.db
+  collection     = mongodb_db_collection.test.name
+  name           = "%s"
+  hidden         = true
+  adopt_existing = %t
+  keys {
+    field = "email"
+    value = "1"
+  }
+}
+
+resource "mongodb_db_index" "rebuilt" {
+  db             = mongodb_db_collection.test.db
+  collection     = mongodb_db_collection.test.name
+  name           = "by_code"
+  adopt_existing = %t
+  adopt_rebuild  = %t
+  keys {
+    field = "code"
+    value = "1"
+  }
+}
+`, dbName, collectionName, emailIndexName, adopt, adopt, rebuild)
+}
@@ -0,0 +1,229 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func resourceDatabaseMaterializedView() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceDatabaseMaterializedViewCreate,
+		ReadContext:   resourceDatabaseMaterializedViewRead,
+		UpdateContext: resourceDatabaseMaterializedViewUpdate,
+		DeleteContext: resourceDatabaseMaterializedViewDelete,
+		CustomizeDiff: resourceDatabaseMaterializedViewCustomizeDiff,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Update: schema.DefaultTimeout(20 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"db": {
+				Type:     schema.TypeString,
+				ForceNew: true,
+				Required: true,
+			},
+			"source": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Collection the pipeline is run on",
+			},
+			"pipeline": {
+				Type:             schema.TypeString,
+				Required:         true,
+				Description:      "Extended JSON array of the aggregation pipeline stages, without the final $merge",
+				ValidateDiagFunc: validateDiagFunc(validation.All(validatePipeline, validatePipelineWithoutOutput)),
+				DiffSuppressFunc: suppressEquivalentPipeline,
+			},
+			"target": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Collection the results are merged into",
+			},
+			"on": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Fields identifying a document in the target, it needs a unique index on them unless it is _id",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"when_matched": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "merge",
+				ValidateDiagFunc: validateDiagFunc(validation.StringInSlice([]string{"replace", "keepExisting", "merge", "fail"}, false)),
+			},
+			"when_not_matched": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "insert",
+				ValidateDiagFunc: validateDiagFunc(validation.StringInSlice([]string{"insert", "discard", "fail"}, false)),
+			},
+			"refresh_trigger": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Any change of this value runs the pipeline again",
+			},
+			"drop_target_on_destroy": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"merged_documents": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of documents the last run merged into the target",
+			},
+		},
+	}
+}
+
+func resourceDatabaseMaterializedViewCreate(ctx context.Context, data *schema.ResourceData, i interface{}) diag.Diagnostics {
+	var db = data.Get("db").(string)
+	var target = data.Get("target").(string)
+
+	diags := refreshMaterializedView(ctx, data, i)
+	if diags != nil {
+		return diags
+	}
+
+	SetId(data, []string{db, target})
+	return resourceDatabaseMaterializedViewRead(ctx, data, i)
+}
+
+func resourceDatabaseMaterializedViewRead(ctx context.Context, data *schema.ResourceData, i interface{}) diag.Diagnostics {
+	dbClient, db, target, err := parseDbAndCollection(data, i)
+	if err != nil {
+		return diag.Errorf("%s", err)
+	}
+
+	// Without its target the next apply has to run the pipeline again
+	names, err := dbClient.ListCollectionNames(context.Background(), bson.M{"name": target})
+	if err != nil {
+		return diag.Errorf("Failed to list collections : %s ", err)
+	}
+	if len(names) == 0 {
+		tflog.Warn(ctx, fmt.Sprintf("Target collection %s no longer exists, removing it from the state", target))
+		data.SetId("")
+		return nil
+	}
+
+	_ = data.Set("db", db)
+	_ = data.Set("target", target)
+	return nil
+}
+
+func resourceDatabaseMaterializedViewUpdate(ctx context.Context, data *schema.ResourceData, i interface{}) diag.Diagnostics {
+	if data.HasChanges("source", "pipeline", "on", "when_matched", "when_not_matched", "refresh_trigger") {
+		diags := refreshMaterializedView(ctx, data, i)
+		if diags != nil {
+			return diags
+		}
+	}
+	return resourceDatabaseMaterializedViewRead(ctx, data, i)
+}
+
+func resourceDatabaseMaterializedViewDelete(ctx context.Context, data *schema.ResourceData, i interface{}) diag.Diagnostics {
+	if !data.Get("drop_target_on_destroy").(bool) {
+		return nil
+	}
+	dbClient, _, target, err := parseDbAndCollection(data, i)
+	if err != nil {
+		return diag.Errorf("%s", err)
+	}
+
+	err = dbClient.Collection(target).Drop(context.Background())
+	if err != nil {
+		return diag.Errorf("%s", err)
+	}
+	return nil
+}
+
+func resourceDatabaseMaterializedViewCustomizeDiff(ctx context.Context, diff *schema.ResourceDiff, i interface{}) error {
+	if diff.Id() != "" && diff.HasChanges("source", "pipeline", "on", "when_matched", "when_not_matched", "refresh_trigger") {
+		return diff.SetNewComputed("merged_documents")
+	}
+	return nil
+}
+
+// validatePipelineWithoutOutput rejects pipelines writing their own output,
+// the $merge stage is added from the resource attributes.
+func validatePipelineWithoutOutput(i interface{}, k string) ([]string, []error) {
+	stages, err := parsePipeline(i.(string))
+	if err != nil {
+		return nil, nil
+	}
+	for _, stage := range stages {
+		for _, element := range stage.(bson.D) {
+			if element.Key == "$merge" || element.Key == "$out" {
+				return nil, []error{fmt.Errorf("%q must not contain a %s stage, set target instead", k, element.Key)}
+			}
+		}
+	}
+	return nil, nil
+}
+
+// refreshMaterializedView runs the pipeline followed by a $merge into the
+// target. $merge reports nothing back, so the documents the pipeline
+// produces are counted by a first run ending in $count.
+func refreshMaterializedView(ctx context.Context, data *schema.ResourceData, i interface{}) diag.Diagnostics {
+	var config = i.(*MongoDatabaseConfiguration)
+	client, connectionError := MongoClientInit(config)
+	if connectionError != nil {
+		return diag.Errorf("Error connecting to db : %s ", connectionError)
+	}
+	var db = data.Get("db").(string)
+	var source = data.Get("source").(string)
+	var target = data.Get("target").(string)
+
+	pipeline, err := parsePipeline(data.Get("pipeline").(string))
+	if err != nil {
+		return diag.Errorf("%s", err)
+	}
+	sourceCollection := client.Database(db).Collection(source)
+
+	count := append(append(bson.A{}, pipeline...), bson.D{{Key: "$count", Value: "merged"}})
+	cursor, err := sourceCollection.Aggregate(ctx, count)
+	if err != nil {
+		return diag.Errorf("Could not run the pipeline : %s ", err)
+	}
+	var counted []struct {
+		Merged int `bson:"merged"`
+	}
+	if err := cursor.All(ctx, &counted); err != nil {
+		return diag.Errorf("Could not run the pipeline : %s ", err)
+	}
+	merged := 0
+	if len(counted) != 0 {
+		merged = counted[0].Merged
+	}
+
+	mergeStage := bson.D{
+		{Key: "into", Value: target},
+		{Key: "whenMatched", Value: data.Get("when_matched").(string)},
+		{Key: "whenNotMatched", Value: data.Get("when_not_matched").(string)},
+	}
+	if on := data.Get("on").([]interface{}); len(on) != 0 {
+		mergeStage = append(mergeStage, bson.E{Key: "on", Value: on})
+	}
+	merge := append(append(bson.A{}, pipeline...), bson.D{{Key: "$merge", Value: mergeStage}})
+	tflog.Info(ctx, fmt.Sprintf("Merging %d documents from %s.%s into %s", merged, db, source, target))
+	cursor, err = sourceCollection.Aggregate(ctx, merge)
+	if err != nil {
+		return diag.Errorf("Could not merge into %s : %s ", target, err)
+	}
+	if err := cursor.Close(ctx); err != nil {
+		return diag.Errorf("Could not merge into %s : %s ", target, err)
+	}
+
+	_ = data.Set("merged_documents", merged)
+	return nil
+}
@@ -0,0 +1,149 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestAccMongoDBMaterializedView_Basic(t *testing.T) {
+	var databaseName = acctest.RandomWithPrefix("tf-acc-db")
+	var sourceName = acctest.RandomWithPrefix("tf-acc-orders")
+	var targetName = acctest.RandomWithPrefix("tf-acc-totals")
+	resourceName := "mongodb_db_materialized_view.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckMongoDBMaterializedViewDestroy,
+		Steps: []resource.TestStep{
+			{
+				PreConfig: testAccMongoDBMaterializedViewInsert(t, databaseName, sourceName,
+					bson.D{{Key: "customer", Value: "a"}, {Key: "total", Value: 10}},
+					bson.D{{Key: "customer", Value: "a"}, {Key: "total", Value: 5}},
+					bson.D{{Key: "customer", Value: "b"}, {Key: "total", Value: 7}},
+				),
+				Config: testAccMongoDBMaterializedView(databaseName, sourceName, targetName, "1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "merged_documents", "2"),
+					testAccCheckMongoDBMaterializedViewTarget(databaseName, targetName, 2),
+				),
+			},
+			{
+				Config:   testAccMongoDBMaterializedView(databaseName, sourceName, targetName, "1"),
+				PlanOnly: true,
+			},
+			{
+				PreConfig: testAccMongoDBMaterializedViewInsert(t, databaseName, sourceName,
+					bson.D{{Key: "customer", Value: "c"}, {Key: "total", Value: 3}},
+				),
+				Config: testAccMongoDBMaterializedView(databaseName, sourceName, targetName, "2"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "merged_documents", "3"),
+					testAccCheckMongoDBMaterializedViewTarget(databaseName, targetName, 3),
+				),
+			},
+		},
+	})
+}
+
+func TestAccMongoDBMaterializedView_InvalidPipeline(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "mongodb_db_materialized_view" "test" {
+  db       = "reporting"
+  source   = "orders"
+  target   = "totals"
+  pipeline = jsonencode([{ "$out" = "totals" }])
+}
+`,
+				ExpectError: regexp.MustCompile("must not contain a \\$out stage"),
+			},
+		},
+	})
+}
+
+func testAccMongoDBMaterializedViewInsert(t *testing.T, db, collectionName string, documents ...interface{}) func() {
+	return func() {
+		client := testAccClient(t)
+		_, err := client.Database(db).Collection(collectionName).InsertMany(context.Background(), documents)
+		if err != nil {
+			t.Fatalf("error inserting documents: %s", err)
+		}
+	}
+}
+
+func testAccCheckMongoDBMaterializedViewTarget(db, target string, expected int64) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		config := testAccProvider.Meta().(*MongoDatabaseConfiguration)
+		client, err := MongoClientInit(config)
+		if err != nil {
+			return fmt.Errorf("error connecting to database: %s", err)
+		}
+
+		count, err := client.Database(db).Collection(target).CountDocuments(context.Background(), bson.D{})
+		if err != nil {
+			return fmt.Errorf("error counting documents: %s", err)
+		}
+		if count != expected {
+			return fmt.Errorf("target %s has %d documents, expected %d", target, count, expected)
+		}
+		return nil
+	}
+}
+
+func testAccCheckMongoDBMaterializedViewDestroy(s *terraform.State) error {
+	config := testAccProvider.Meta().(*MongoDatabaseConfiguration)
+	client, err := MongoClientInit(config)
+	if err != nil {
+		return fmt.Errorf("error connecting to database: %s", err)
+	}
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "mongodb_db_materialized_view" {
+			continue
+		}
+
+		db, target, err := resourceDatabaseCollectionParseId(rs.Primary.ID)
+		if err != nil {
+			continue // If we can't parse the ID, assume it's destroyed
+		}
+
+		names, err := client.Database(db).ListCollectionNames(context.Background(), bson.M{"name": target})
+		if err != nil {
+			continue // If we can't list collections, assume it's destroyed
+		}
+		if len(names) != 0 {
+			return fmt.Errorf("target %s still exists in database %s", target, db)
+		}
+	}
+
+	return nil
+}
+
+func testAccMongoDBMaterializedView(dbName, sourceName, targetName, trigger string) string {
+	return fmt.Sprintf(`
+resource "mongodb_db_materialized_view" "test" {
+  db                     = "%s"
+  source                 = "%s"
+  target                 = "%s"
+  when_matched           = "replace"
+  refresh_trigger        = "%s"
+  drop_target_on_destroy = true
+
+  pipeline = jsonencode([
+    { "$group" = { _id = "$customer", total = { "$sum" = "$total" } } },
+  ])
+}
+`, dbName, sourceName, targetName, trigger)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"reflect"
 	"sort"
 	"strings"
 
@@ -19,6 +20,7 @@ func resourceDatabaseRole() *schema.Resource {
 		ReadContext:   resourceDatabaseRoleRead,
 		UpdateContext: resourceDatabaseRoleUpdate,
 		DeleteContext: resourceDatabaseRoleDelete,
+		CustomizeDiff: resourceDatabaseRoleCustomizeDiff,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -26,16 +28,17 @@ func resourceDatabaseRole() *schema.Resource {
 			"database": {
 				Type:     schema.TypeString,
 				Optional: true,
+				ForceNew: true,
 				Default:  "admin",
 			},
 			"name": {
 				Type:     schema.TypeString,
 				Required: true,
+				ForceNew: true,
 			},
 			"privilege": {
 				Type:     schema.TypeSet,
 				Optional: true,
-				MaxItems: 10,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 
@@ -47,10 +50,29 @@ func resourceDatabaseRole() *schema.Resource {
 							Type:     schema.TypeString,
 							Optional: true,
 						},
+						"system_buckets": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "Grant on the time series buckets of `collection` instead of the collection itself",
+						},
+						"cluster": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "Grant cluster-wide actions, db and collection must be empty",
+						},
+						"any_resource": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "Grant on every resource in the system, db and collection must be empty",
+						},
 
 						"actions": {
-							Type:     schema.TypeList,
-							Optional: true,
+							Type:     schema.TypeSet,
+							Required: true,
+							MinItems: 1,
 							Elem: &schema.Schema{
 								Type: schema.TypeString,
 							},
@@ -61,7 +83,6 @@ func resourceDatabaseRole() *schema.Resource {
 			"inherited_role": {
 				Type:     schema.TypeSet,
 				Optional: true,
-				MaxItems: 2,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"db": {
@@ -75,6 +96,7 @@ func resourceDatabaseRole() *schema.Resource {
 					},
 				},
 			},
+			"authentication_restriction": authenticationRestrictionSchema(),
 		},
 	}
 }
@@ -88,7 +110,6 @@ func resourceDatabaseRoleCreate(ctx context.Context, data *schema.ResourceData,
 	var role = data.Get("name").(string)
 	var database = data.Get("database").(string)
 	var roleList []Role
-	var privileges []PrivilegeDto
 
 	privilege := data.Get("privilege").(*schema.Set).List()
 	roles := data.Get("inherited_role").(*schema.Set).List()
@@ -97,12 +118,14 @@ func resourceDatabaseRoleCreate(ctx context.Context, data *schema.ResourceData,
 	if roleMapErr != nil {
 		return diag.Errorf("Error decoding map : %s ", roleMapErr)
 	}
-	privMapErr := mapstructure.Decode(privilege, &privileges)
+	privileges, privMapErr := decodePrivileges(privilege)
 	if privMapErr != nil {
 		return diag.Errorf("Error decoding map : %s ", privMapErr)
 	}
 
-	err := createRole(client, role, roleList, privileges, database)
+	restrictions := expandAuthenticationRestrictions(data.Get("authentication_restriction").([]interface{}))
+
+	err := createRole(client, role, roleList, privileges, restrictions, database)
 
 	if err != nil {
 		return diag.Errorf("Could not create the role : %s ", err)
@@ -142,7 +165,6 @@ func resourceDatabaseRoleUpdate(ctx context.Context, data *schema.ResourceData,
 	if connectionError != nil {
 		return diag.Errorf("Error connecting to database : %s ", connectionError)
 	}
-	var role = data.Get("name").(string)
 	var stateId = data.State().ID
 	roleName, database, err := resourceDatabaseRoleParseId(stateId)
 
@@ -150,36 +172,36 @@ func resourceDatabaseRoleUpdate(ctx context.Context, data *schema.ResourceData,
 		return diag.Errorf("%s", err)
 	}
 
-	db := client.Database(database)
-	result := db.RunCommand(context.Background(), bson.D{{Key: "dropRole", Value: roleName}})
-
-	if result.Err() != nil {
-		return diag.Errorf("%s", result.Err())
-	}
-
+	// Only the changed fields are sent, nil leaves them as they are
 	var roleList []Role
 	var privileges []PrivilegeDto
-
-	privilege := data.Get("privilege").(*schema.Set).List()
-	roles := data.Get("inherited_role").(*schema.Set).List()
-
-	roleMapErr := mapstructure.Decode(roles, &roleList)
-	if roleMapErr != nil {
-		return diag.Errorf("Error decoding map : %s ", roleMapErr)
+	var restrictions []AuthenticationRestriction
+
+	if data.HasChange("inherited_role") {
+		roleList = []Role{}
+		roles := data.Get("inherited_role").(*schema.Set).List()
+		roleMapErr := mapstructure.Decode(roles, &roleList)
+		if roleMapErr != nil {
+			return diag.Errorf("Error decoding map : %s ", roleMapErr)
+		}
 	}
-	privMapErr := mapstructure.Decode(privilege, &privileges)
-	if privMapErr != nil {
-		return diag.Errorf("Error decoding map : %s ", privMapErr)
+	if data.HasChange("privilege") {
+		var privMapErr error
+		privilege := data.Get("privilege").(*schema.Set).List()
+		privileges, privMapErr = decodePrivileges(privilege)
+		if privMapErr != nil {
+			return diag.Errorf("Error decoding map : %s ", privMapErr)
+		}
 	}
 
-	err2 := createRole(client, role, roleList, privileges, database)
+	if data.HasChange("authentication_restriction") {
+		restrictions = expandAuthenticationRestrictions(data.Get("authentication_restriction").([]interface{}))
+	}
 
-	if err2 != nil {
-		return diag.Errorf("Could not create the role  :  %s ", err)
+	err = updateRole(client, roleName, roleList, privileges, restrictions, database)
+	if err != nil {
+		return diag.Errorf("Could not update the role : %s ", err)
 	}
-	str := database + "." + role
-	encoded := base64.StdEncoding.EncodeToString([]byte(str))
-	data.SetId(encoded)
 
 	return resourceDatabaseRoleRead(ctx, data, i)
 }
@@ -218,21 +240,32 @@ func resourceDatabaseRoleRead(ctx context.Context, data *schema.ResourceData, i
 	privileges := make([]interface{}, len(result.Roles[0].Privileges))
 
 	for i, s := range result.Roles[0].Privileges {
-		// Sort actions to ensure consistent ordering
-		actions := make([]string, len(s.Actions))
-		copy(actions, s.Actions)
-		sort.Strings(actions)
-		
-		privileges[i] = map[string]interface{}{
-			"db":         s.Resource.Db,
-			"collection": s.Resource.Collection,
-			"actions":    actions,
+		privilege := map[string]interface{}{
+			"db":             stringValue(s.Resource.Db),
+			"collection":     stringValue(s.Resource.Collection),
+			"system_buckets": s.Resource.SystemBuckets != nil,
+			"cluster":        s.Resource.Cluster,
+			"any_resource":   s.Resource.AnyResource,
+			"actions":        s.Actions,
+		}
+		// Buckets are modelled as the collection they belong to
+		if s.Resource.SystemBuckets != nil {
+			privilege["collection"] = *s.Resource.SystemBuckets
 		}
+		privileges[i] = privilege
 	}
 	dataSetError = data.Set("privilege", privileges)
 	if dataSetError != nil {
 		return diag.Errorf("Error setting role privilege : %s ", err)
 	}
+	restrictions, err := decodeAuthenticationRestrictions(result.Roles[0].AuthenticationRestrictions)
+	if err != nil {
+		return diag.Errorf("Error decoding role authentication restrictions : %s ", err)
+	}
+	dataSetError = data.Set("authentication_restriction", flattenAuthenticationRestrictions(restrictions))
+	if dataSetError != nil {
+		return diag.Errorf("Error setting role authentication restrictions : %s ", dataSetError)
+	}
 	dataSetError = data.Set("database", database)
 	if dataSetError != nil {
 		return diag.Errorf("Error setting role database : %s ", err)
@@ -247,6 +280,83 @@ func resourceDatabaseRoleRead(ctx context.Context, data *schema.ResourceData, i
 	return diags
 }
 
+func resourceDatabaseRoleCustomizeDiff(ctx context.Context, diff *schema.ResourceDiff, i interface{}) error {
+	// The server merges privileges on the same resource, which would never converge.
+	// Unknown db and collection values read as "" until apply, so resources
+	// are only compared once they are all known.
+	privilegesKnown := diff.NewValueKnown("privilege")
+	if config := diff.GetRawConfig(); privilegesKnown && !config.IsNull() && config.IsKnown() {
+		privilegesKnown = config.GetAttr("privilege").IsWhollyKnown()
+	}
+	resources := make(map[string]bool)
+	for _, element := range diff.Get("privilege").(*schema.Set).List() {
+		privilege := element.(map[string]interface{})
+		resource := toPrivileges([]PrivilegeDto{{
+			Db:            privilege["db"].(string),
+			Collection:    privilege["collection"].(string),
+			SystemBuckets: privilege["system_buckets"].(bool),
+			Cluster:       privilege["cluster"].(bool),
+			AnyResource:   privilege["any_resource"].(bool),
+		}})[0].Resource.String()
+		if privilegesKnown && resources[resource] {
+			return fmt.Errorf("privilege resource%s is declared more than once, merge its actions into a single privilege", resource)
+		}
+		resources[resource] = true
+
+		kinds := 0
+		for _, kind := range []string{"system_buckets", "cluster", "any_resource"} {
+			if privilege[kind].(bool) {
+				kinds++
+			}
+		}
+		if kinds > 1 {
+			return fmt.Errorf("privilege can only set one of system_buckets, cluster and any_resource")
+		}
+		if (privilege["cluster"].(bool) || privilege["any_resource"].(bool)) &&
+			(privilege["db"].(string) != "" || privilege["collection"].(string) != "") {
+			return fmt.Errorf("privilege db and collection must be empty for cluster and any_resource resources")
+		}
+	}
+	if diff.NewValueKnown("name") && diff.NewValueKnown("database") {
+		for _, element := range diff.Get("inherited_role").(*schema.Set).List() {
+			inherited := element.(map[string]interface{})
+			db := inherited["db"].(string)
+			if db == "" {
+				db = diff.Get("database").(string)
+			}
+			if inherited["role"] == diff.Get("name") && db == diff.Get("database") {
+				return fmt.Errorf("role %s can not inherit from itself", diff.Get("name"))
+			}
+		}
+	}
+	return nil
+}
+
+// decodePrivileges converts the privilege blocks, with their actions in a
+// canonical order so the commands sent are stable.
+func decodePrivileges(privilege []interface{}) ([]PrivilegeDto, error) {
+	privileges := []PrivilegeDto{}
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook: func(from reflect.Type, to reflect.Type, value interface{}) (interface{}, error) {
+			if set, ok := value.(*schema.Set); ok {
+				return set.List(), nil
+			}
+			return value, nil
+		},
+		Result: &privileges,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := decoder.Decode(privilege); err != nil {
+		return nil, err
+	}
+	for _, element := range privileges {
+		sort.Strings(element.Actions)
+	}
+	return privileges, nil
+}
+
 func resourceDatabaseRoleParseId(id string) (string, string, error) {
 	result, errEncoding := base64.StdEncoding.DecodeString(id)
 
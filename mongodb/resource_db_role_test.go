@@ -3,10 +3,14 @@ package mongodb
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
@@ -96,6 +100,296 @@ func TestAccMongoDBRole_MultiplePrivileges(t *testing.T) {
 	})
 }
 
+func TestAccMongoDBRole_Update(t *testing.T) {
+	var roleName = acctest.RandomWithPrefix("tf-acc-role")
+	var databaseName = acctest.RandomWithPrefix("tf-acc-db")
+	resourceName := "mongodb_db_role.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckMongoDBRoleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccMongoDBRoleBasic(databaseName, roleName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMongoDBRoleExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "privilege.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "inherited_role.#", "0"),
+				),
+			},
+			{
+				Config: testAccMongoDBRoleWithInheritedRoles(databaseName, roleName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMongoDBRoleExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "privilege.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "inherited_role.#", "1"),
+				),
+			},
+			{
+				Config: testAccMongoDBRoleMultiplePrivileges(databaseName, roleName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMongoDBRoleExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "privilege.#", "2"),
+					resource.TestCheckResourceAttr(resourceName, "inherited_role.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccMongoDBRole_ResourceKinds(t *testing.T) {
+	var roleName = acctest.RandomWithPrefix("tf-acc-role")
+	var databaseName = acctest.RandomWithPrefix("tf-acc-db")
+	resourceName := "mongodb_db_role.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckMongoDBRoleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccMongoDBRoleResourceKinds(databaseName, roleName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMongoDBRoleExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "database", "admin"),
+					resource.TestCheckResourceAttr(resourceName, "privilege.#", "4"),
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "privilege.*", map[string]string{
+						"cluster":   "true",
+						"actions.#": "3",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "privilege.*", map[string]string{
+						"any_resource": "true",
+						"actions.#":    "1",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "privilege.*", map[string]string{
+						"db":             databaseName,
+						"collection":     "metrics",
+						"system_buckets": "true",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "privilege.*", map[string]string{
+						"db":         databaseName,
+						"collection": "",
+						"cluster":    "false",
+					}),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccMongoDBRole_InvalidResourceKind(t *testing.T) {
+	var roleName = acctest.RandomWithPrefix("tf-acc-role")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "mongodb_db_role" "test" {
+  name = "%s"
+
+  privilege {
+    db      = "admin"
+    cluster = true
+    actions = ["inprog"]
+  }
+}
+`, roleName),
+				ExpectError: regexp.MustCompile("db and collection must be empty"),
+			},
+		},
+	})
+}
+
+func TestDecodePrivileges(t *testing.T) {
+	privileges, err := decodePrivileges([]interface{}{
+		map[string]interface{}{
+			"db":             "metrics",
+			"collection":     "weather",
+			"system_buckets": true,
+			"cluster":        false,
+			"any_resource":   false,
+			"actions":        schema.NewSet(schema.HashString, []interface{}{"insert", "find"}),
+		},
+		map[string]interface{}{
+			"db":             "",
+			"collection":     "",
+			"system_buckets": false,
+			"cluster":        false,
+			"any_resource":   true,
+			"actions":        schema.NewSet(schema.HashString, []interface{}{"anyAction"}),
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := []PrivilegeDto{
+		{Db: "metrics", Collection: "weather", SystemBuckets: true, Actions: []string{"find", "insert"}},
+		{AnyResource: true, Actions: []string{"anyAction"}},
+	}
+	if !reflect.DeepEqual(privileges, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, privileges)
+	}
+	if resource := toPrivileges(privileges)[0].Resource; resource.SystemBuckets == nil || *resource.SystemBuckets != "weather" {
+		t.Fatalf("expected a system_buckets resource on weather, got %s", resource.String())
+	}
+}
+
+func TestAccMongoDBRole_ManyPrivileges(t *testing.T) {
+	var roleName = acctest.RandomWithPrefix("tf-acc-role")
+	var databaseName = acctest.RandomWithPrefix("tf-acc-db")
+	resourceName := "mongodb_db_role.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckMongoDBRoleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccMongoDBRoleManyPrivileges(databaseName, roleName, 30),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMongoDBRoleExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "privilege.#", "30"),
+					resource.TestCheckResourceAttr(resourceName, "inherited_role.#", "4"),
+				),
+			},
+			{
+				Config:   testAccMongoDBRoleManyPrivileges(databaseName, roleName, 30),
+				PlanOnly: true,
+			},
+			{
+				Config: testAccMongoDBRoleManyPrivileges(databaseName, roleName, 12),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMongoDBRoleExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "privilege.#", "12"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccMongoDBRole_InvalidPrivileges(t *testing.T) {
+	var roleName = acctest.RandomWithPrefix("tf-acc-role")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "mongodb_db_role" "test" {
+  name = "%s"
+
+  privilege {
+    db      = "app"
+    actions = ["find"]
+  }
+
+  privilege {
+    db      = "app"
+    actions = ["insert"]
+  }
+}
+`, roleName),
+				ExpectError: regexp.MustCompile("is declared more than once"),
+			},
+			{
+				Config: fmt.Sprintf(`
+resource "mongodb_db_role" "test" {
+  name = "%s"
+
+  inherited_role {
+    role = "%s"
+  }
+}
+`, roleName, roleName),
+				ExpectError: regexp.MustCompile("can not inherit from itself"),
+			},
+			{
+				// The collection is only known after apply and must not be
+				// taken for the privilege on the whole database
+				Config: fmt.Sprintf(`
+resource "terraform_data" "collection" {
+  input = "orders"
+}
+
+resource "mongodb_db_role" "test" {
+  name = "%s"
+
+  privilege {
+    db      = "app"
+    actions = ["find"]
+  }
+
+  privilege {
+    db         = "app"
+    collection = terraform_data.collection.output
+    actions    = ["insert"]
+  }
+}
+`, roleName),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestAccMongoDBRole_AuthenticationRestrictions(t *testing.T) {
+	var roleName = acctest.RandomWithPrefix("tf-acc-role")
+	var databaseName = acctest.RandomWithPrefix("tf-acc-db")
+	resourceName := "mongodb_db_role.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckMongoDBRoleDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccMongoDBRoleRestrictions(databaseName, roleName, "0.0.0.0/0"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMongoDBRoleExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "authentication_restriction.#", "1"),
+					resource.TestCheckTypeSetElemAttr(resourceName, "authentication_restriction.0.client_source.*", "0.0.0.0/0"),
+					resource.TestCheckTypeSetElemAttr(resourceName, "authentication_restriction.0.server_address.*", "0.0.0.0/0"),
+				),
+			},
+			{
+				Config: testAccMongoDBRoleRestrictions(databaseName, roleName, "127.0.0.1"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMongoDBRoleExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "authentication_restriction.#", "1"),
+					resource.TestCheckTypeSetElemAttr(resourceName, "authentication_restriction.0.client_source.*", "127.0.0.1"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config: testAccMongoDBRoleBasic(databaseName, roleName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMongoDBRoleExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "authentication_restriction.#", "0"),
+				),
+			},
+			{
+				Config:      testAccMongoDBRoleRestrictions(databaseName, roleName, "10.0.0.0/33"),
+				ExpectError: regexp.MustCompile("10.0.0.0/33"),
+			},
+		},
+	})
+}
+
 func testAccCheckMongoDBRoleExists(resourceName string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[resourceName]
@@ -207,6 +501,57 @@ resource "mongodb_db_role" "test" {
 `, dbName, roleName, dbName, dbName)
 }
 
+func testAccMongoDBRoleResourceKinds(dbName, roleName string) string {
+	return fmt.Sprintf(`
+resource "mongodb_db_role" "test" {
+  database = "admin"
+  name     = "%s"
+
+  privilege {
+    cluster = true
+    actions = ["inprog", "replSetGetStatus", "serverStatus"]
+  }
+
+  privilege {
+    any_resource = true
+    actions      = ["find"]
+  }
+
+  privilege {
+    db             = "%s"
+    collection     = "metrics"
+    system_buckets = true
+    actions        = ["find"]
+  }
+
+  privilege {
+    db      = "%s"
+    actions = ["find", "listCollections"]
+  }
+}
+`, roleName, dbName, dbName)
+}
+
+func testAccMongoDBRoleRestrictions(dbName, roleName, clientSource string) string {
+	return fmt.Sprintf(`
+resource "mongodb_db_role" "test" {
+  database = "%s"
+  name     = "%s"
+
+  privilege {
+    db         = "%s"
+    collection = "test_collection"
+    actions    = ["find", "insert", "update"]
+  }
+
+  authentication_restriction {
+    client_source  = ["%s"]
+    server_address = ["0.0.0.0/0"]
+  }
+}
+`, dbName, roleName, dbName, clientSource)
+}
+
 func testAccMongoDBRoleMultiplePrivileges(dbName, roleName string) string {
 	return fmt.Sprintf(`
 resource "mongodb_db_role" "test" {
@@ -227,3 +572,44 @@ resource "mongodb_db_role" "test" {
 }
 `, dbName, roleName, dbName, dbName)
 }
+
+// testAccMongoDBRoleManyPrivileges declares actions out of order so Read has
+// to return them in a stable form for the plan to stay empty.
+func testAccMongoDBRoleManyPrivileges(dbName, roleName string, count int) string {
+	var privileges strings.Builder
+	for i := 0; i < count; i++ {
+		fmt.Fprintf(&privileges, `
+  privilege {
+    db         = "%s"
+    collection = "collection%02d"
+    actions    = ["update", "find", "remove", "insert"]
+  }
+`, dbName, i)
+	}
+	return fmt.Sprintf(`
+resource "mongodb_db_role" "test" {
+  database = "%s"
+  name     = "%s"
+%s
+  inherited_role {
+    db   = "%s"
+    role = "read"
+  }
+
+  inherited_role {
+    db   = "%s"
+    role = "dbAdmin"
+  }
+
+  inherited_role {
+    db   = "%s"
+    role = "readWrite"
+  }
+
+  inherited_role {
+    db   = "%s"
+    role = "userAdmin"
+  }
+}
+`, dbName, roleName, privileges.String(), dbName, dbName, dbName, dbName)
+}
@@ -4,13 +4,16 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"regexp"
 	"strings"
 
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/mitchellh/mapstructure"
 	"go.mongodb.org/mongo-driver/v2/bson"
-	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
 func resourceDatabaseUser() *schema.Resource {
@@ -19,13 +22,18 @@ func resourceDatabaseUser() *schema.Resource {
 		ReadContext:   resourceDatabaseUserRead,
 		UpdateContext: resourceDatabaseUserUpdate,
 		DeleteContext: resourceDatabaseUserDelete,
+		CustomizeDiff: resourceDatabaseUserCustomizeDiff,
+		ValidateRawResourceConfigFuncs: []schema.ValidateRawResourceConfigFunc{
+			validation.PreferWriteOnlyAttribute(cty.GetAttrPath("password"), cty.GetAttrPath("password_wo")),
+		},
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
 		Schema: map[string]*schema.Schema{
 			"auth_database": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The database of the user, `$external` for X.509, LDAP and AWS IAM users",
 			},
 			"name": {
 				Type:     schema.TypeString,
@@ -33,14 +41,35 @@ func resourceDatabaseUser() *schema.Resource {
 				ForceNew: true,
 			},
 			"password": {
-				Type:      schema.TypeString,
-				Required:  true,
-				Sensitive: true,
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				ConflictsWith: []string{"password_wo"},
+				Description:   "The user password, required unless auth_database is `$external` or password_wo is set",
+			},
+			"password_wo": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				WriteOnly:     true,
+				ConflictsWith: []string{"password"},
+				RequiredWith:  []string{"password_version"},
+				Description:   "Write-only user password, never stored in state. Applied on create and whenever password_version changes",
+			},
+			"password_version": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Change this value to apply password_wo to an existing user",
+			},
+			"detect_password_drift": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Authenticate as the user on refresh and plan a password reset when the stored password is rejected",
 			},
 			"role": {
 				Type:     schema.TypeSet,
 				Optional: true,
-				MaxItems: 25,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"db": {
@@ -54,6 +83,24 @@ func resourceDatabaseUser() *schema.Resource {
 					},
 				},
 			},
+			"mechanisms": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Computed:    true,
+				Description: "The SCRAM mechanisms the user credentials are created for",
+				Elem: &schema.Schema{
+					Type:             schema.TypeString,
+					ValidateDiagFunc: validateDiagFunc(validation.StringInSlice([]string{"SCRAM-SHA-1", "SCRAM-SHA-256"}, false)),
+				},
+			},
+			"custom_data": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Description:      "A JSON document of arbitrary information about the user, e.g. its owner",
+				ValidateDiagFunc: validateDiagFunc(validation.StringIsJSON),
+				DiffSuppressFunc: suppressEquivalentCustomData,
+			},
+			"authentication_restriction": authenticationRestrictionSchema(),
 		},
 	}
 }
@@ -65,17 +112,13 @@ func resourceDatabaseUserDelete(ctx context.Context, data *schema.ResourceData,
 		return diag.Errorf("Error connecting to database : %s ", connectionError)
 	}
 	var stateId = data.State().ID
-	var database = data.Get("auth_database").(string)
 
-	id, errEncoding := base64.StdEncoding.DecodeString(stateId)
-	if errEncoding != nil {
-		return diag.Errorf("ID mismatch %s", errEncoding)
+	// StateID is a concatenation of database and username, the username may itself contain dots.
+	userName, database, err := resourceDatabaseUserParseId(stateId)
+	if err != nil {
+		return diag.Errorf("ID mismatch %s", err)
 	}
 
-	// StateID is a concatenation of database and username. We only use the username here.
-	splitId := strings.Split(string(id), ".")
-	userName := splitId[1]
-
 	adminDB := client.Database(database)
 
 	result := adminDB.RunCommand(context.Background(), bson.D{{Key: "dropUser", Value: userName}})
@@ -103,9 +146,12 @@ func resourceDatabaseUserUpdate(ctx context.Context, data *schema.ResourceData,
 
 	adminDB := client.Database(database)
 
-	// Only update if password or roles have changed
-	if data.HasChange("password") || data.HasChange("role") {
-		var userPassword = data.Get("password").(string)
+	// Only update the attributes that have changed
+	if data.HasChanges("password", "password_version", "role", "mechanisms", "custom_data", "authentication_restriction") {
+		userPassword, diags := resourceDatabaseUserPassword(data)
+		if diags.HasError() {
+			return diags
+		}
 		var roleList []Role
 		roles := data.Get("role").(*schema.Set).List()
 		roleMapErr := mapstructure.Decode(roles, &roleList)
@@ -113,21 +159,35 @@ func resourceDatabaseUserUpdate(ctx context.Context, data *schema.ResourceData,
 			return diag.Errorf("Error decoding map : %s ", roleMapErr)
 		}
 
-		var result *mongo.SingleResult
+		command := bson.D{{Key: "updateUser", Value: userName}}
+		// Credentials for new mechanisms can only be built from the password
+		if userPassword != "" && data.HasChanges("password", "password_version", "mechanisms") {
+			command = append(command, bson.E{Key: "pwd", Value: userPassword})
+		}
 		if len(roleList) != 0 {
-			result = adminDB.RunCommand(context.Background(), bson.D{
-				{Key: "updateUser", Value: userName},
-				{Key: "pwd", Value: userPassword},
-				{Key: "roles", Value: roleList},
-			})
+			command = append(command, bson.E{Key: "roles", Value: roleList})
 		} else {
-			result = adminDB.RunCommand(context.Background(), bson.D{
-				{Key: "updateUser", Value: userName},
-				{Key: "pwd", Value: userPassword},
-				{Key: "roles", Value: []bson.M{}},
-			})
+			command = append(command, bson.E{Key: "roles", Value: []bson.M{}})
+		}
+		if mechanisms := expandStringSet(data.Get("mechanisms").(*schema.Set)); data.HasChange("mechanisms") && len(mechanisms) != 0 {
+			command = append(command, bson.E{Key: "mechanisms", Value: mechanisms})
+		}
+		if data.HasChange("custom_data") {
+			customData, err := parseCustomData(data.Get("custom_data").(string))
+			if err != nil {
+				return diag.Errorf("%s", err)
+			}
+			if customData == nil {
+				customData = bson.D{}
+			}
+			command = append(command, bson.E{Key: "customData", Value: customData})
+		}
+		if data.HasChange("authentication_restriction") {
+			restrictions := expandAuthenticationRestrictions(data.Get("authentication_restriction").([]interface{}))
+			command = append(command, bson.E{Key: "authenticationRestrictions", Value: restrictions})
 		}
 
+		result := adminDB.RunCommand(context.Background(), command)
 		if result.Err() != nil {
 			return diag.Errorf("Could not update the user : %s ", result.Err())
 		}
@@ -177,10 +237,60 @@ func resourceDatabaseUserRead(ctx context.Context, data *schema.ResourceData, i
 	if dataSetError != nil {
 		return diag.Errorf("error setting name : %s ", dataSetError)
 	}
-	dataSetError = data.Set("password", data.Get("password"))
+	password := data.Get("password").(string)
+	// A login from an address outside an authentication restriction of the
+	// user or of one of its roles fails like a wrong password, so the check
+	// is only done for unrestricted users
+	checkDrift := data.Get("detect_password_drift").(bool) && password != "" &&
+		len(data.Get("authentication_restriction").([]interface{})) == 0
+	if checkDrift {
+		restricted, err := hasInheritedAuthenticationRestrictions(client, username, database)
+		if err != nil {
+			return diag.Errorf("Error checking the user password : %s ", err)
+		}
+		checkDrift = !restricted
+	}
+	if checkDrift {
+		valid, err := checkUserPassword(config, username, password, database)
+		if err != nil {
+			return diag.Errorf("Error checking the user password : %s ", err)
+		}
+		if !valid {
+			// Forget the stored password so the next plan resets it
+			tflog.Info(ctx, fmt.Sprintf("password of user %s in %s was changed outside of Terraform", username, database))
+			password = ""
+		}
+	}
+	dataSetError = data.Set("password", password)
 	if dataSetError != nil {
 		return diag.Errorf("error setting password : %s ", dataSetError)
 	}
+	dataSetError = data.Set("mechanisms", result.Users[0].Mechanisms)
+	if dataSetError != nil {
+		return diag.Errorf("error setting mechanisms : %s ", dataSetError)
+	}
+	customData := ""
+	if len(result.Users[0].CustomData) > 0 {
+		if elements, _ := result.Users[0].CustomData.Elements(); len(elements) > 0 {
+			customDataBytes, err := bson.MarshalExtJSON(result.Users[0].CustomData, false, false)
+			if err != nil {
+				return diag.Errorf("error encoding custom data : %s ", err)
+			}
+			customData = string(customDataBytes)
+		}
+	}
+	dataSetError = data.Set("custom_data", customData)
+	if dataSetError != nil {
+		return diag.Errorf("error setting custom data : %s ", dataSetError)
+	}
+	restrictions, err := decodeAuthenticationRestrictions(result.Users[0].AuthenticationRestrictions)
+	if err != nil {
+		return diag.Errorf("Error decoding authentication restrictions : %s ", err)
+	}
+	dataSetError = data.Set("authentication_restriction", flattenAuthenticationRestrictions(restrictions))
+	if dataSetError != nil {
+		return diag.Errorf("error setting authentication restrictions : %s ", dataSetError)
+	}
 	data.SetId(stateID)
 	return nil
 }
@@ -193,18 +303,28 @@ func resourceDatabaseUserCreate(ctx context.Context, data *schema.ResourceData,
 	}
 	var database = data.Get("auth_database").(string)
 	var userName = data.Get("name").(string)
-	var userPassword = data.Get("password").(string)
+	userPassword, diags := resourceDatabaseUserPassword(data)
+	if diags.HasError() {
+		return diags
+	}
 	var roleList []Role
+	customData, err := parseCustomData(data.Get("custom_data").(string))
+	if err != nil {
+		return diag.Errorf("%s", err)
+	}
 	var user = DbUser{
-		Name:     userName,
-		Password: userPassword,
+		Name:                       userName,
+		Password:                   userPassword,
+		Mechanisms:                 expandStringSet(data.Get("mechanisms").(*schema.Set)),
+		CustomData:                 customData,
+		AuthenticationRestrictions: expandAuthenticationRestrictions(data.Get("authentication_restriction").([]interface{})),
 	}
 	roles := data.Get("role").(*schema.Set).List()
 	roleMapErr := mapstructure.Decode(roles, &roleList)
 	if roleMapErr != nil {
 		return diag.Errorf("Error decoding map : %s ", roleMapErr)
 	}
-	err := createUser(client, user, roleList, database)
+	err = createUser(client, user, roleList, database)
 	if err != nil {
 		return diag.Errorf("Could not create the user : %s ", err)
 	}
@@ -214,6 +334,132 @@ func resourceDatabaseUserCreate(ctx context.Context, data *schema.ResourceData,
 	return resourceDatabaseUserRead(ctx, data, i)
 }
 
+// externalDatabase holds users authenticated outside of MongoDB (X.509, LDAP, AWS IAM).
+const externalDatabase = "$external"
+
+func resourceDatabaseUserCustomizeDiff(ctx context.Context, diff *schema.ResourceDiff, i interface{}) error {
+	if !diff.NewValueKnown("auth_database") || !diff.NewValueKnown("name") || !diff.NewValueKnown("password") {
+		return nil
+	}
+	passwordWo, known := rawConfigString(diff.GetRawConfig(), "password_wo")
+	if !known {
+		return nil
+	}
+	var database = diff.Get("auth_database").(string)
+	var userName = diff.Get("name").(string)
+	var password = diff.Get("password").(string) + passwordWo
+
+	if database != externalDatabase {
+		if password == "" {
+			return fmt.Errorf("password or password_wo is required for users in database %q", database)
+		}
+		return nil
+	}
+
+	if password != "" {
+		return fmt.Errorf("password must not be set for users in the %s database", externalDatabase)
+	}
+	if mechanisms, ok := diff.GetOk("mechanisms"); ok && diff.HasChange("mechanisms") && mechanisms.(*schema.Set).Len() > 0 {
+		return fmt.Errorf("mechanisms can not be set for users in the %s database", externalDatabase)
+	}
+	// X.509 users are named after their certificate subject
+	if strings.Contains(userName, "=") {
+		return validateSubjectDN(userName)
+	}
+	return nil
+}
+
+var attributeTypeRegexp = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9-]*|[0-9]+(\.[0-9]+)*)$`)
+
+// validateSubjectDN checks that name is an RFC 4514 distinguished name such as
+// CN=client,OU=terraform,O=example.
+func validateSubjectDN(name string) error {
+	for _, rdn := range splitUnescaped(name, ',') {
+		for _, attribute := range splitUnescaped(rdn, '+') {
+			attributeType, value, found := strings.Cut(attribute, "=")
+			if !found || strings.TrimSpace(value) == "" {
+				return fmt.Errorf("invalid subject %q: %q is not a type=value pair", name, attribute)
+			}
+			if !attributeTypeRegexp.MatchString(strings.TrimSpace(attributeType)) {
+				return fmt.Errorf("invalid subject %q: %q is not a valid attribute type", name, attributeType)
+			}
+		}
+	}
+	return nil
+}
+
+// splitUnescaped splits s on sep, ignoring separators escaped with a backslash.
+func splitUnescaped(s string, sep byte) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case sep:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// resourceDatabaseUserPassword returns the configured password, taken from the
+// write-only password_wo when set. Write-only values are only available from
+// the configuration during create and update.
+func resourceDatabaseUserPassword(data *schema.ResourceData) (string, diag.Diagnostics) {
+	if password := data.Get("password").(string); password != "" {
+		return password, nil
+	}
+	passwordWo, diags := data.GetRawConfigAt(cty.GetAttrPath("password_wo"))
+	if diags.HasError() {
+		return "", diags
+	}
+	if !passwordWo.IsKnown() || passwordWo.IsNull() {
+		return "", nil
+	}
+	return passwordWo.AsString(), nil
+}
+
+// rawConfigString returns a string attribute of a raw configuration and
+// whether its value is known yet.
+func rawConfigString(config cty.Value, attribute string) (string, bool) {
+	if config.IsNull() || !config.IsKnown() {
+		return "", true
+	}
+	value := config.GetAttr(attribute)
+	if !value.IsKnown() {
+		return "", false
+	}
+	if value.IsNull() {
+		return "", true
+	}
+	return value.AsString(), true
+}
+
+// parseCustomData converts the custom_data JSON into a document, nil when empty.
+func parseCustomData(customData string) (bson.D, error) {
+	if customData == "" {
+		return nil, nil
+	}
+	var document bson.D
+	if err := bson.UnmarshalExtJSON([]byte(customData), false, &document); err != nil {
+		return nil, fmt.Errorf("Invalid custom_data JSON: %s", err)
+	}
+	return document, nil
+}
+
+// suppressEquivalentCustomData is suppressEquivalentJSON that also treats an
+// empty document as no custom data, which is how Read stores it.
+func suppressEquivalentCustomData(k, old, new string, d *schema.ResourceData) bool {
+	oldData, oldErr := parseCustomData(old)
+	newData, newErr := parseCustomData(new)
+	if oldErr == nil && newErr == nil && len(oldData) == 0 && len(newData) == 0 {
+		return true
+	}
+	return suppressEquivalentJSON(k, old, new, d)
+}
+
 func resourceDatabaseUserParseId(id string) (string, string, error) {
 	result, errEncoding := base64.StdEncoding.DecodeString(id)
 
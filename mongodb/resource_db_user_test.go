@@ -3,6 +3,7 @@ package mongodb
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
@@ -157,6 +158,244 @@ func TestAccMongoDBUser_AdminDatabase(t *testing.T) {
 	})
 }
 
+func TestAccMongoDBUser_WriteOnlyPassword(t *testing.T) {
+	var userName = acctest.RandomWithPrefix("tf-acc-user")
+	var password = acctest.RandomWithPrefix("tf-acc-pwd")
+	var updatedPassword = acctest.RandomWithPrefix("tf-acc-pwd-upd")
+	var databaseName = acctest.RandomWithPrefix("tf-acc-db")
+	resourceName := "mongodb_db_user.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckMongoDBUserDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccMongoDBUserWriteOnly(databaseName, userName, password, 1),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMongoDBUserExists(resourceName),
+					resource.TestCheckNoResourceAttr(resourceName, "password_wo"),
+					resource.TestCheckResourceAttr(resourceName, "password", ""),
+					resource.TestCheckResourceAttr(resourceName, "password_version", "1"),
+					testAccCheckMongoDBUserAuthenticates(resourceName, password),
+				),
+			},
+			// Changing the write-only value alone is not applied
+			{
+				Config: testAccMongoDBUserWriteOnly(databaseName, userName, updatedPassword, 1),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMongoDBUserAuthenticates(resourceName, password),
+				),
+			},
+			{
+				Config: testAccMongoDBUserWriteOnly(databaseName, userName, updatedPassword, 2),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "password_version", "2"),
+					testAccCheckMongoDBUserAuthenticates(resourceName, updatedPassword),
+				),
+			},
+		},
+	})
+}
+
+func TestAccMongoDBUser_PasswordDrift(t *testing.T) {
+	var userName = acctest.RandomWithPrefix("tf-acc-user")
+	var password = acctest.RandomWithPrefix("tf-acc-pwd")
+	var databaseName = acctest.RandomWithPrefix("tf-acc-db")
+	resourceName := "mongodb_db_user.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckMongoDBUserDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccMongoDBUserPasswordDrift(databaseName, userName, password),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMongoDBUserExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "detect_password_drift", "true"),
+					testAccCheckMongoDBUserAuthenticates(resourceName, password),
+				),
+			},
+			// Change the password with mongosh-like updateUser, the refresh must notice it
+			{
+				PreConfig: func() {
+					config := testAccProvider.Meta().(*MongoDatabaseConfiguration)
+					client, err := MongoClientInit(config)
+					if err != nil {
+						t.Fatalf("error connecting to database: %s", err)
+					}
+					result := client.Database(databaseName).RunCommand(context.Background(), bson.D{
+						{Key: "updateUser", Value: userName},
+						{Key: "pwd", Value: acctest.RandomWithPrefix("tf-acc-out-of-band")},
+					})
+					if result.Err() != nil {
+						t.Fatalf("error changing password: %s", result.Err())
+					}
+				},
+				Config:             testAccMongoDBUserPasswordDrift(databaseName, userName, password),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccMongoDBUserPasswordDrift(databaseName, userName, password),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "password", password),
+					testAccCheckMongoDBUserAuthenticates(resourceName, password),
+				),
+			},
+		},
+	})
+}
+
+func TestAccMongoDBUser_RestrictionsAndCustomData(t *testing.T) {
+	var userName = acctest.RandomWithPrefix("tf-acc-user")
+	var password = acctest.RandomWithPrefix("tf-acc-pwd")
+	var databaseName = acctest.RandomWithPrefix("tf-acc-db")
+	resourceName := "mongodb_db_user.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckMongoDBUserDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccMongoDBUserRestrictions(databaseName, userName, password, "team-a", "0.0.0.0/0"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMongoDBUserExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "mechanisms.#", "1"),
+					resource.TestCheckTypeSetElemAttr(resourceName, "mechanisms.*", "SCRAM-SHA-256"),
+					resource.TestCheckResourceAttr(resourceName, "custom_data", `{"owner":"team-a"}`),
+					resource.TestCheckResourceAttr(resourceName, "authentication_restriction.#", "1"),
+					resource.TestCheckTypeSetElemAttr(resourceName, "authentication_restriction.0.client_source.*", "0.0.0.0/0"),
+					resource.TestCheckTypeSetElemAttr(resourceName, "authentication_restriction.0.server_address.*", "0.0.0.0/0"),
+				),
+			},
+			{
+				Config: testAccMongoDBUserRestrictions(databaseName, userName, password, "team-b", "127.0.0.1"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMongoDBUserExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "custom_data", `{"owner":"team-b"}`),
+					resource.TestCheckTypeSetElemAttr(resourceName, "authentication_restriction.0.client_source.*", "127.0.0.1"),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"password"},
+			},
+			{
+				Config:      testAccMongoDBUserRestrictions(databaseName, userName, password, "team-b", "not-an-address"),
+				ExpectError: regexp.MustCompile("not-an-address"),
+			},
+		},
+	})
+}
+
+func TestAccMongoDBUser_External(t *testing.T) {
+	var subject = fmt.Sprintf("CN=%s,OU=terraform,O=terraform-provider-mongodb", acctest.RandomWithPrefix("tf-acc-x509"))
+	var databaseName = acctest.RandomWithPrefix("tf-acc-db")
+	resourceName := "mongodb_db_user.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckMongoDBUserDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccMongoDBUserExternal(databaseName, subject, "read"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMongoDBUserExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "auth_database", "$external"),
+					resource.TestCheckResourceAttr(resourceName, "name", subject),
+					resource.TestCheckResourceAttr(resourceName, "password", ""),
+					resource.TestCheckResourceAttr(resourceName, "role.#", "1"),
+				),
+			},
+			{
+				Config: testAccMongoDBUserExternal(databaseName, subject, "readWrite"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMongoDBUserExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "role.#", "1"),
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "role.*", map[string]string{"role": "readWrite"}),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccMongoDBUser_ExternalInvalid(t *testing.T) {
+	var databaseName = acctest.RandomWithPrefix("tf-acc-db")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccMongoDBUserExternal(databaseName, "CN=client,OU", "read"),
+				ExpectError: regexp.MustCompile("is not a type=value pair"),
+			},
+			{
+				Config:      testAccMongoDBUserBasic("$external", "CN=client", "secret"),
+				ExpectError: regexp.MustCompile("password must not be set"),
+			},
+		},
+	})
+}
+
+func TestValidateSubjectDN(t *testing.T) {
+	valid := []string{
+		"CN=client",
+		"CN=client.example.com,OU=terraform,O=example",
+		"CN=Doe\\, John,O=example",
+		"CN=client+UID=42,O=example",
+		"2.5.4.3=client",
+	}
+	for _, name := range valid {
+		if err := validateSubjectDN(name); err != nil {
+			t.Errorf("expected %q to be valid: %s", name, err)
+		}
+	}
+
+	invalid := []string{"CN=", "CN=client,OU", "=client", "CN=client,,O=example", "C N=client"}
+	for _, name := range invalid {
+		if err := validateSubjectDN(name); err == nil {
+			t.Errorf("expected %q to be invalid", name)
+		}
+	}
+}
+
+func TestSuppressEquivalentCustomData(t *testing.T) {
+	equivalent := [][2]string{
+		{"", "{}"},
+		{"{}", ""},
+		{"", " { } "},
+		{`{"owner":"team-a","tier":1}`, `{ "tier": 1, "owner": "team-a" }`},
+	}
+	for _, values := range equivalent {
+		if !suppressEquivalentCustomData("custom_data", values[0], values[1], nil) {
+			t.Errorf("expected %q and %q to be equivalent", values[0], values[1])
+		}
+	}
+
+	different := [][2]string{
+		{"", `{"owner":"team-a"}`},
+		{"{}", `{"owner":"team-a"}`},
+		{`{"owner":"team-a"}`, `{"owner":"team-b"}`},
+	}
+	for _, values := range different {
+		if suppressEquivalentCustomData("custom_data", values[0], values[1], nil) {
+			t.Errorf("expected %q and %q to differ", values[0], values[1])
+		}
+	}
+}
+
 func testAccCheckMongoDBUserExists(resourceName string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[resourceName]
@@ -192,6 +431,38 @@ func testAccCheckMongoDBUserExists(resourceName string) resource.TestCheckFunc {
 	}
 }
 
+// testAccCheckMongoDBUserAuthenticates verifies the user can log in with password
+func testAccCheckMongoDBUserAuthenticates(resourceName string, password string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", resourceName)
+		}
+
+		userName, database, err := resourceDatabaseUserParseId(rs.Primary.ID)
+		if err != nil {
+			return fmt.Errorf("error parsing ID: %s", err)
+		}
+
+		config := testAccProvider.Meta().(*MongoDatabaseConfiguration)
+		userConfig := *config.Config
+		userConfig.Username = userName
+		userConfig.Password = password
+		userConfig.DB = database
+
+		client, err := userConfig.MongoClient()
+		if err != nil {
+			return fmt.Errorf("error creating client: %s", err)
+		}
+		defer func() { _ = client.Disconnect(context.Background()) }()
+
+		if err := client.Ping(context.Background(), nil); err != nil {
+			return fmt.Errorf("user %s could not authenticate: %s", userName, err)
+		}
+		return nil
+	}
+}
+
 func testAccCheckMongoDBUserDestroy(s *terraform.State) error {
 	config := testAccProvider.Meta().(*MongoDatabaseConfiguration)
 	client, err := MongoClientInit(config)
@@ -267,6 +538,74 @@ resource "mongodb_db_user" "test" {
 `, dbName, userName, password, dbName, dbName)
 }
 
+func testAccMongoDBUserPasswordDrift(dbName, userName, password string) string {
+	return fmt.Sprintf(`
+resource "mongodb_db_user" "test" {
+  auth_database         = "%s"
+  name                  = "%s"
+  password              = "%s"
+  detect_password_drift = true
+
+  role {
+    db   = "%s"
+    role = "read"
+  }
+}
+`, dbName, userName, password, dbName)
+}
+
+func testAccMongoDBUserWriteOnly(dbName, userName, password string, version int) string {
+	return fmt.Sprintf(`
+resource "mongodb_db_user" "test" {
+  auth_database    = "%s"
+  name             = "%s"
+  password_wo      = "%s"
+  password_version = %d
+
+  role {
+    db   = "%s"
+    role = "read"
+  }
+}
+`, dbName, userName, password, version, dbName)
+}
+
+func testAccMongoDBUserRestrictions(dbName, userName, password, owner, clientSource string) string {
+	return fmt.Sprintf(`
+resource "mongodb_db_user" "test" {
+  auth_database = "%s"
+  name          = "%s"
+  password      = "%s"
+  mechanisms    = ["SCRAM-SHA-256"]
+  custom_data   = jsonencode({ owner = "%s" })
+
+  authentication_restriction {
+    client_source  = ["%s"]
+    server_address = ["0.0.0.0/0"]
+  }
+
+  role {
+    db   = "%s"
+    role = "read"
+  }
+}
+`, dbName, userName, password, owner, clientSource, dbName)
+}
+
+func testAccMongoDBUserExternal(dbName, subject, role string) string {
+	return fmt.Sprintf(`
+resource "mongodb_db_user" "test" {
+  auth_database = "$external"
+  name          = "%s"
+
+  role {
+    db   = "%s"
+    role = "%s"
+  }
+}
+`, subject, dbName, role)
+}
+
 func testAccMongoDBUserAdminDatabase(userName, password string) string {
 	return fmt.Sprintf(`
 resource "mongodb_db_user" "test" {
@@ -0,0 +1,205 @@
+package mongodb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+func resourceDatabaseView() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceDatabaseViewCreate,
+		ReadContext:   resourceDatabaseViewRead,
+		UpdateContext: resourceDatabaseViewUpdate,
+		DeleteContext: resourceDatabaseViewDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"db": {
+				Type:     schema.TypeString,
+				ForceNew: true,
+				Required: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"view_on": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Collection or view the view is computed from",
+			},
+			"pipeline": {
+				Type:             schema.TypeString,
+				Required:         true,
+				Description:      "Extended JSON array of the aggregation pipeline stages",
+				ValidateDiagFunc: validateDiagFunc(validatePipeline),
+				DiffSuppressFunc: suppressEquivalentPipeline,
+			},
+		},
+	}
+}
+
+func resourceDatabaseViewCreate(ctx context.Context, data *schema.ResourceData, i interface{}) diag.Diagnostics {
+	var config = i.(*MongoDatabaseConfiguration)
+	client, connectionError := MongoClientInit(config)
+	if connectionError != nil {
+		return diag.Errorf("Error connecting to db : %s ", connectionError)
+	}
+	var db = data.Get("db").(string)
+	var viewName = data.Get("name").(string)
+
+	pipeline, err := parsePipeline(data.Get("pipeline").(string))
+	if err != nil {
+		return diag.Errorf("%s", err)
+	}
+
+	err = client.Database(db).CreateView(context.Background(), viewName, data.Get("view_on").(string), pipeline)
+	if err != nil {
+		return diag.Errorf("Could not create the view : %s ", err)
+	}
+
+	SetId(data, []string{db, viewName})
+	return resourceDatabaseViewRead(ctx, data, i)
+}
+
+func resourceDatabaseViewRead(ctx context.Context, data *schema.ResourceData, i interface{}) diag.Diagnostics {
+	dbClient, db, viewName, err := parseDbAndCollection(data, i)
+	if err != nil {
+		return diag.Errorf("%s", err)
+	}
+
+	specification, err := getCollectionSpecification(dbClient, viewName)
+	if err != nil {
+		return diag.Errorf("%s", err)
+	}
+	if specification.Type != "view" {
+		return diag.Errorf("%s is a %s, not a view", viewName, specification.Type)
+	}
+
+	viewOn, _ := specification.Options.Lookup("viewOn").StringValueOK()
+	pipeline, err := marshalPipeline(specification.Options.Lookup("pipeline"))
+	if err != nil {
+		return diag.Errorf("Failed to encode pipeline : %s ", err)
+	}
+
+	_ = data.Set("db", db)
+	_ = data.Set("name", viewName)
+	_ = data.Set("view_on", viewOn)
+	_ = data.Set("pipeline", pipeline)
+	return nil
+}
+
+func resourceDatabaseViewUpdate(ctx context.Context, data *schema.ResourceData, i interface{}) diag.Diagnostics {
+	dbClient, _, viewName, err := parseDbAndCollection(data, i)
+	if err != nil {
+		return diag.Errorf("%s", err)
+	}
+
+	pipeline, err := parsePipeline(data.Get("pipeline").(string))
+	if err != nil {
+		return diag.Errorf("%s", err)
+	}
+
+	// collMod replaces the definition as a whole, so both are always sent
+	result := dbClient.RunCommand(context.Background(), bson.D{
+		{Key: "collMod", Value: viewName},
+		{Key: "viewOn", Value: data.Get("view_on").(string)},
+		{Key: "pipeline", Value: pipeline},
+	})
+	if result.Err() != nil {
+		return diag.Errorf("Failed to update the view : %s ", result.Err())
+	}
+
+	return resourceDatabaseViewRead(ctx, data, i)
+}
+
+func resourceDatabaseViewDelete(ctx context.Context, data *schema.ResourceData, i interface{}) diag.Diagnostics {
+	dbClient, _, viewName, err := parseDbAndCollection(data, i)
+	if err != nil {
+		return diag.Errorf("%s", err)
+	}
+
+	err = dbClient.Collection(viewName).Drop(context.Background())
+	if err != nil {
+		return diag.Errorf("%s", err)
+	}
+	return nil
+}
+
+func getCollectionSpecification(dbClient *mongo.Database, name string) (*mongo.CollectionSpecification, error) {
+	specifications, err := dbClient.ListCollectionSpecifications(context.Background(), bson.M{"name": name})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list collections : %s ", err)
+	}
+	if len(specifications) == 0 {
+		return nil, fmt.Errorf("%s does not exist", name)
+	}
+	return &specifications[0], nil
+}
+
+func parsePipeline(pipeline string) (bson.A, error) {
+	var stages bson.A
+	if err := bson.UnmarshalExtJSON([]byte(pipeline), false, &stages); err != nil {
+		return nil, fmt.Errorf("invalid pipeline Extended JSON: %s", err)
+	}
+	for _, stage := range stages {
+		if _, ok := stage.(bson.D); !ok {
+			return nil, fmt.Errorf("invalid pipeline: every stage must be a document")
+		}
+	}
+	return stages, nil
+}
+
+// marshalPipeline encodes a pipeline as relaxed Extended JSON. The encoder
+// only writes documents, so the array is wrapped in one and extracted again.
+func marshalPipeline(pipeline interface{}) (string, error) {
+	wrapped, err := bson.MarshalExtJSON(bson.D{{Key: "pipeline", Value: pipeline}}, false, false)
+	if err != nil {
+		return "", err
+	}
+	var unwrapped struct {
+		Pipeline json.RawMessage `json:"pipeline"`
+	}
+	if err := json.Unmarshal(wrapped, &unwrapped); err != nil {
+		return "", err
+	}
+	return string(unwrapped.Pipeline), nil
+}
+
+func validatePipeline(i interface{}, k string) ([]string, []error) {
+	value, ok := i.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+	}
+	if _, err := parsePipeline(value); err != nil {
+		return nil, []error{fmt.Errorf("%q: %s", k, err)}
+	}
+	return nil, nil
+}
+
+// suppressEquivalentPipeline ignores formatting, key order and Extended JSON
+// notation differences between two pipelines.
+func suppressEquivalentPipeline(k, old, new string, d *schema.ResourceData) bool {
+	if old == new {
+		return true
+	}
+	oldStages, oldErr := parsePipeline(old)
+	newStages, newErr := parsePipeline(new)
+	if oldErr != nil || newErr != nil {
+		return false
+	}
+	oldValue, oldErr := marshalPipeline(oldStages)
+	newValue, newErr := marshalPipeline(newStages)
+	if oldErr != nil || newErr != nil {
+		return false
+	}
+	return suppressEquivalentJSON(k, oldValue, newValue, d)
+}
@@ -0,0 +1,140 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestAccMongoDBView_Basic(t *testing.T) {
+	var viewName = acctest.RandomWithPrefix("tf-acc-view")
+	var collectionName = acctest.RandomWithPrefix("tf-acc-test")
+	var databaseName = acctest.RandomWithPrefix("tf-acc-db")
+	resourceName := "mongodb_db_view.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckMongoDBViewDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccMongoDBView(databaseName, collectionName, viewName, "active"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMongoDBViewExists(resourceName, collectionName),
+					resource.TestCheckResourceAttr(resourceName, "db", databaseName),
+					resource.TestCheckResourceAttr(resourceName, "name", viewName),
+					resource.TestCheckResourceAttr(resourceName, "view_on", collectionName),
+				),
+			},
+			{
+				Config:   testAccMongoDBView(databaseName, collectionName, viewName, "active"),
+				PlanOnly: true,
+			},
+			{
+				Config: testAccMongoDBView(databaseName, collectionName, viewName, "archived"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckMongoDBViewExists(resourceName, collectionName),
+					resource.TestCheckResourceAttr(resourceName, "pipeline", `[{"$match":{"status":"archived"}},{"$project":{"_id":0,"name":1,"total":1}}]`),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckMongoDBViewExists(resourceName, viewOn string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("not found: %s", resourceName)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("no ID is set")
+		}
+
+		config := testAccProvider.Meta().(*MongoDatabaseConfiguration)
+		client, err := MongoClientInit(config)
+		if err != nil {
+			return fmt.Errorf("error connecting to database: %s", err)
+		}
+
+		db, viewName, err := resourceDatabaseCollectionParseId(rs.Primary.ID)
+		if err != nil {
+			return fmt.Errorf("error parsing ID: %s", err)
+		}
+
+		specification, err := getCollectionSpecification(client.Database(db), viewName)
+		if err != nil {
+			return err
+		}
+		if specification.Type != "view" {
+			return fmt.Errorf("%s is a %s, not a view", viewName, specification.Type)
+		}
+		if current, _ := specification.Options.Lookup("viewOn").StringValueOK(); current != viewOn {
+			return fmt.Errorf("view %s is on %s, expected %s", viewName, current, viewOn)
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckMongoDBViewDestroy(s *terraform.State) error {
+	config := testAccProvider.Meta().(*MongoDatabaseConfiguration)
+	client, err := MongoClientInit(config)
+	if err != nil {
+		return fmt.Errorf("error connecting to database: %s", err)
+	}
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "mongodb_db_view" {
+			continue
+		}
+
+		db, viewName, err := resourceDatabaseCollectionParseId(rs.Primary.ID)
+		if err != nil {
+			continue // If we can't parse the ID, assume it's destroyed
+		}
+
+		cursor, err := client.Database(db).ListCollections(context.Background(), bson.M{"name": viewName})
+		if err != nil {
+			continue // If we can't list collections, assume it's destroyed
+		}
+
+		if cursor.Next(context.Background()) {
+			return fmt.Errorf("view %s still exists in database %s", viewName, db)
+		}
+	}
+
+	return nil
+}
+
+func testAccMongoDBView(dbName, collectionName, viewName, status string) string {
+	return fmt.Sprintf(`
+resource "mongodb_db_collection" "test" {
+  db                  = "%s"
+  name                = "%s"
+  deletion_protection = false
+}
+
+resource "mongodb_db_view" "test" {
+  db      = mongodb_db_collection.test.db
+  name    = "%s"
+  view_on = mongodb_db_collection.test.name
+
+  pipeline = jsonencode([
+    { "$match" = { status = "%s" } },
+    { "$project" = { _id = 0, name = 1, total = 1 } },
+  ])
+}
+`, dbName, collectionName, viewName, status)
+}